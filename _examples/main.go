@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
 )
 
 func main() {
@@ -20,13 +22,17 @@ func main() {
 
 // List Certificate
 func listCertificate(ctx context.Context, g *goacm.GoACM) {
-	if certificates, err := goacm.ListCertificates(ctx, g.ACMClient); err != nil {
+	certificates, err := goacm.ListCertificates(ctx, g.ACMClient)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if err := render.Certificates(os.Stdout, render.FormatTable, certificates, func(o *render.Options) {
+		o.Columns = []string{"domainName", "status", "arn"}
+		o.SortBy = "domainName"
+	}); err != nil {
 		fmt.Println(err.Error())
-	} else {
-		fmt.Println("DomainName\tStatus\tARN")
-		for _, c := range certificates {
-			fmt.Printf("%s\t%s\t%s\n", c.DomainName, c.Status, c.Arn)
-		}
 	}
 }
 
@@ -38,8 +44,9 @@ func getCertificate(ctx context.Context, g *goacm.GoACM, arn string) {
 		return
 	}
 
-	fmt.Println("DomainName\tStatus\tARN")
-	fmt.Printf("%s\t%s\t%s\n", c.DomainName, c.Status, c.Arn)
+	if err := render.Certificate(os.Stdout, render.FormatYAML, c); err != nil {
+		fmt.Println(err.Error())
+	}
 }
 
 // Issue a Certificate
@@ -50,7 +57,9 @@ func issueCertificate(ctx context.Context, g *goacm.GoACM, targetDomain, hostedD
 		return
 	}
 
-	fmt.Printf("ARN: %v", res.CertificateArn)
+	if err := render.IssueCertificateResult(os.Stdout, render.FormatJSON, res); err != nil {
+		fmt.Println(err.Error())
+	}
 }
 
 // Delete a Certificate
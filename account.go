@@ -0,0 +1,180 @@
+package goacm
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// accountAPI is implemented by API clients that know the account they belong to.
+type accountAPI interface {
+	accountLabel() string
+}
+
+// accountACMAPI is an ACMAPI that wraps errors with the account ID.
+type accountACMAPI struct {
+	api       ACMAPI
+	accountID string
+}
+
+// accountRoute53API is a Route53API that wraps errors with the account ID.
+type accountRoute53API struct {
+	api       Route53API
+	accountID string
+}
+
+// NewAccountACMAPI returns an ACMAPI that returns errors as ServiceError with the account ID.
+func NewAccountACMAPI(api ACMAPI, accountID string) ACMAPI {
+	return accountACMAPI{api: api, accountID: accountID}
+}
+
+// NewAccountRoute53API returns a Route53API that returns errors as ServiceError with the account ID.
+func NewAccountRoute53API(api Route53API, accountID string) Route53API {
+	return accountRoute53API{api: api, accountID: accountID}
+}
+
+func (a accountACMAPI) accountLabel() string {
+	return serviceLabel(ServiceACM, a.accountID)
+}
+
+func (a accountACMAPI) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ServiceError{Service: ServiceACM, AccountID: a.accountID, Err: err}
+}
+
+// ListCertificates calls ACM ListCertificates.
+func (a accountACMAPI) ListCertificates(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+	out, err := a.api.ListCertificates(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// DescribeCertificate calls ACM DescribeCertificate.
+func (a accountACMAPI) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+	out, err := a.api.DescribeCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// DeleteCertificate calls ACM DeleteCertificate.
+func (a accountACMAPI) DeleteCertificate(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+	out, err := a.api.DeleteCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// RequestCertificate calls ACM RequestCertificate.
+func (a accountACMAPI) RequestCertificate(ctx context.Context, params *acm.RequestCertificateInput, optFns ...func(*acm.Options)) (*acm.RequestCertificateOutput, error) {
+	out, err := a.api.RequestCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// ImportCertificate calls ACM ImportCertificate.
+func (a accountACMAPI) ImportCertificate(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+	out, err := a.api.ImportCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// ExportCertificate calls ACM ExportCertificate.
+func (a accountACMAPI) ExportCertificate(ctx context.Context, params *acm.ExportCertificateInput, optFns ...func(*acm.Options)) (*acm.ExportCertificateOutput, error) {
+	out, err := a.api.ExportCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// ListTagsForCertificate calls ACM ListTagsForCertificate.
+func (a accountACMAPI) ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
+	out, err := a.api.ListTagsForCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// AddTagsToCertificate calls ACM AddTagsToCertificate.
+func (a accountACMAPI) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	out, err := a.api.AddTagsToCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// RemoveTagsFromCertificate calls ACM RemoveTagsFromCertificate.
+func (a accountACMAPI) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	out, err := a.api.RemoveTagsFromCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// UpdateCertificateOptions calls ACM UpdateCertificateOptions.
+func (a accountACMAPI) UpdateCertificateOptions(ctx context.Context, params *acm.UpdateCertificateOptionsInput, optFns ...func(*acm.Options)) (*acm.UpdateCertificateOptionsOutput, error) {
+	out, err := a.api.UpdateCertificateOptions(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// RenewCertificate calls ACM RenewCertificate.
+func (a accountACMAPI) RenewCertificate(ctx context.Context, params *acm.RenewCertificateInput, optFns ...func(*acm.Options)) (*acm.RenewCertificateOutput, error) {
+	out, err := a.api.RenewCertificate(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// ResendValidationEmail calls ACM ResendValidationEmail.
+func (a accountACMAPI) ResendValidationEmail(ctx context.Context, params *acm.ResendValidationEmailInput, optFns ...func(*acm.Options)) (*acm.ResendValidationEmailOutput, error) {
+	out, err := a.api.ResendValidationEmail(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+func (a accountRoute53API) accountLabel() string {
+	return serviceLabel(ServiceRoute53, a.accountID)
+}
+
+func (a accountRoute53API) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ServiceError{Service: ServiceRoute53, AccountID: a.accountID, Err: err}
+}
+
+// ListHostedZones calls Route 53 ListHostedZones.
+func (a accountRoute53API) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	out, err := a.api.ListHostedZones(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// ListResourceRecordSets calls Route 53 ListResourceRecordSets.
+func (a accountRoute53API) ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	out, err := a.api.ListResourceRecordSets(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// ChangeResourceRecordSets calls Route 53 ChangeResourceRecordSets.
+func (a accountRoute53API) ChangeResourceRecordSets(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+	out, err := a.api.ChangeResourceRecordSets(ctx, params, optFns...)
+	return out, a.wrap(err)
+}
+
+// Returns a label of the service and the account of the API client, or service if the account is unknown.
+func apiLabel(api interface{}, service string) string {
+	if a, ok := api.(accountAPI); ok {
+		return a.accountLabel()
+	}
+	return service
+}
+
+// Returns the account ID of the role, or "" if the role or its ARN is invalid.
+func roleAccountID(role *AssumeRole) string {
+	if role == nil {
+		return ""
+	}
+	a, err := arn.Parse(role.RoleArn)
+	if err != nil {
+		return ""
+	}
+	return a.AccountID
+}
+
+// Returns the ID of the account of the credentials with STS GetCallerIdentity, or "" if it fails.
+// The account is used only in errors, so a failure does not prevent using the clients.
+func callerAccountID(ctx context.Context, api STSGetCallerIdentityAPI) string {
+	out, err := api.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return ""
+	}
+	return aws.ToString(out.Account)
+}
@@ -0,0 +1,96 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DeleteCertificate_CrossAccount(t *testing.T) {
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:              "arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn",
+				Type:             string(types.CertificateTypeAmazonIssued),
+				ValidationMethod: string(types.ValidationMethodDns),
+				ValidationRecordSet: goacm.RecordSet{
+					HostedDomainName: "example.com",
+					Name:             "_validation.name.test.example.com",
+					Value:            "_validation.value.test.example.com",
+					Type:             string(route53Types.RRTypeCname),
+				},
+			},
+		},
+	}
+
+	rp := []goacm.MockRoute53Params{
+		{
+			RecordSet: goacm.RecordSet{
+				HostedDomainName: "example.com",
+				Name:             "_validation.name.test.example.com",
+				Value:            "_validation.value.test.example.com",
+				Type:             string(route53Types.RRTypeCname),
+			},
+			ChangeAction: route53Types.ChangeActionDelete,
+		},
+	}
+
+	cases := []struct {
+		name          string
+		route53Params []goacm.MockRoute53Params
+		arn           string
+		wantErr       bool
+		expectService string
+		expectAccount string
+		expectMessage string
+	}{
+		{
+			name:          "normal",
+			route53Params: rp,
+			arn:           ap[0].Certificate.Arn,
+			wantErr:       false,
+		},
+		{
+			name:          "error: certificate not found in ACM account",
+			route53Params: rp,
+			arn:           "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-exists-arn",
+			wantErr:       true,
+			expectService: goacm.ServiceACM,
+			expectAccount: "000000000000",
+			expectMessage: "ACM (account 000000000000)",
+		},
+		{
+			name:          "error: hosted zone not found in Route 53 account",
+			route53Params: []goacm.MockRoute53Params{},
+			arn:           ap[0].Certificate.Arn,
+			wantErr:       true,
+			expectMessage: "Route 53 (account 111111111111)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			aAPI := goacm.NewAccountACMAPI(goacm.NewMockACMAPI(ap), "000000000000")
+			rAPI := goacm.NewAccountRoute53API(goacm.NewMockRoute53API(c.route53Params), "111111111111")
+			err := goacm.DeleteCertificate(context.TODO(), aAPI, rAPI, c.arn)
+			if !c.wantErr {
+				assert.NoError(tt, err)
+				return
+			}
+
+			assert.Error(tt, err)
+			assert.Contains(tt, err.Error(), c.expectMessage)
+			if c.expectService != "" {
+				var se *goacm.ServiceError
+				assert.True(tt, errors.As(err, &se))
+				assert.Equal(tt, c.expectService, se.Service)
+				assert.Equal(tt, c.expectAccount, se.AccountID)
+			}
+		})
+	}
+}
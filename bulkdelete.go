@@ -0,0 +1,340 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// DefaultBulkDeleteConcurrency is the number of certificates BulkDeleteCertificates deletes at once by default.
+const DefaultBulkDeleteConcurrency = 5
+
+// CertificateFilter is a structure that represents conditions of certificates.
+// A certificate matches if it meets all the conditions that are set.
+type CertificateFilter struct {
+	// Statuses are the statuses of certificates to match, such as EXPIRED or VALIDATION_TIMED_OUT.
+	Statuses []string `json:"statuses,omitempty" yaml:"statuses,omitempty"`
+
+	// OlderThan matches certificates created more than the duration ago.
+	OlderThan time.Duration `json:"olderThan,omitempty" yaml:"olderThan,omitempty"`
+
+	// Tags matches certificates that have all the tags with the same values.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// DomainPattern is a shell pattern such as "*.example.com" that matches the domain name case-insensitively.
+	DomainPattern string `json:"domainPattern,omitempty" yaml:"domainPattern,omitempty"`
+
+	// NotInUse matches certificates that are not in use by any resources.
+	NotInUse bool `json:"notInUse,omitempty" yaml:"notInUse,omitempty"`
+}
+
+// IsEmpty returns true if no conditions are set, and the filter matches all certificates.
+func (f CertificateFilter) IsEmpty() bool {
+	return len(f.Statuses) == 0 && f.OlderThan == 0 && len(f.Tags) == 0 && f.DomainPattern == "" && !f.NotInUse
+}
+
+// Validate returns an error if the domain pattern is malformed or the duration is negative.
+func (f CertificateFilter) Validate() error {
+	if _, err := path.Match(f.DomainPattern, ""); err != nil {
+		return fmt.Errorf("invalid domain pattern %q: %w", f.DomainPattern, err)
+	}
+	if f.OlderThan < 0 {
+		return fmt.Errorf("older than must not be negative: %s", f.OlderThan)
+	}
+	return nil
+}
+
+// Match returns true if the certificate meets the conditions except Tags, which are checked with MatchTags.
+func (f CertificateFilter) Match(c Certificate, now time.Time) bool {
+	if len(f.Statuses) > 0 && !containsString(f.Statuses, c.Status) {
+		return false
+	}
+	if f.OlderThan > 0 && (c.CreatedAt == nil || now.Sub(*c.CreatedAt) < f.OlderThan) {
+		return false
+	}
+	if f.DomainPattern != "" && !matchDomainName(f.DomainPattern, c.DomainName) {
+		return false
+	}
+	if f.NotInUse && len(c.InUseBy) > 0 {
+		return false
+	}
+	return true
+}
+
+// MatchTags returns true if the tags contain all the tags of the filter with the same values.
+func (f CertificateFilter) MatchTags(tags map[string]string) bool {
+	for k, v := range f.Tags {
+		if tv, ok := tags[k]; !ok || tv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// BulkDeleteOutcome is a type that represents the outcome of a certificate in BulkDeleteCertificates.
+type BulkDeleteOutcome string
+
+// Outcomes of bulk delete.
+const (
+	BulkDeleteOutcomeDeleted BulkDeleteOutcome = "DELETED"
+	BulkDeleteOutcomeSkipped BulkDeleteOutcome = "SKIPPED"
+	BulkDeleteOutcomeFailed  BulkDeleteOutcome = "FAILED"
+)
+
+// BulkDeleteResult is a structure that represents the outcome of a certificate that matched the filter.
+type BulkDeleteResult struct {
+	CertificateArn string            `json:"certificateArn" yaml:"certificateArn"`
+	DomainName     string            `json:"domainName" yaml:"domainName"`
+	Status         string            `json:"status" yaml:"status"`
+	Outcome        BulkDeleteOutcome `json:"outcome" yaml:"outcome"`
+	Error          string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// BulkDeleteOptions is a structure that represents options for BulkDeleteCertificates.
+type BulkDeleteOptions struct {
+	// Concurrency is the number of certificates to delete at once. Default is DefaultBulkDeleteConcurrency.
+	Concurrency int
+
+	// Confirm is called for each certificate that matched the filter in order before deleting any of them,
+	// and the certificate is skipped if it returns false. If it returns an error, nothing is deleted.
+	// Default confirms all certificates.
+	Confirm func(ctx context.Context, c Certificate) (bool, error)
+
+	// Now is the time to compare with OlderThan of the filter. Default is the current time.
+	Now time.Time
+
+	// Delete are options for deleting each certificate.
+	Delete []func(*DeleteCertificateOptions)
+}
+
+// BulkDeleteCertificates deletes the certificates that match the filter with the records that validate the domains.
+// The filter must not be empty, so that all certificates are not deleted by mistake.
+// The certificates are deleted concurrently up to the concurrency, and then the records that no other certificates use
+// are deleted once each, because a record is the same for the certificates of a domain. Records of certificates
+// that fail to be deleted are kept. Nothing is deleted if describing any certificate fails,
+// because the records it uses are unknown.
+// It returns a result of each certificate that matched the filter in the order ACM listed them,
+// and an error that joins the errors of the certificates and their records.
+func BulkDeleteCertificates(ctx context.Context, aAPI ACMAPI, rAPI Route53API, filter CertificateFilter, optFns ...func(*BulkDeleteOptions)) ([]BulkDeleteResult, error) {
+	o := BulkDeleteOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultBulkDeleteConcurrency
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	do := DeleteCertificateOptions{}
+	for _, fn := range o.Delete {
+		fn(&do)
+	}
+
+	if filter.IsEmpty() {
+		return nil, errors.New("filter must have at least one condition")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	summaries, err := ListCertificateSummaries(ctx, aAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []BulkDeleteResult{}
+	confirmed := []bulkDeleteTarget{}
+	used := map[RecordSet]bool{}
+	for _, s := range summaries {
+		arn := aws.ToString(s.CertificateArn)
+		out, err := aAPI.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: s.CertificateArn})
+		var nfe *acmTypes.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			// the certificate has been deleted after being listed, and uses no records
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", arn, err)
+		}
+
+		c := toCertificate(arn, out.Certificate)
+		rsList := validationRecordSets(out.Certificate)
+		target, err := bulkDeleteMatch(ctx, aAPI, filter, o, c)
+		if err != nil {
+			results = append(results, BulkDeleteResult{CertificateArn: arn, DomainName: c.DomainName, Status: c.Status, Outcome: BulkDeleteOutcomeFailed, Error: err.Error()})
+		}
+		if !target {
+			for _, rs := range rsList {
+				used[rs] = true
+			}
+			continue
+		}
+
+		r := BulkDeleteResult{CertificateArn: arn, DomainName: c.DomainName, Status: c.Status, Outcome: BulkDeleteOutcomeSkipped}
+		if o.Confirm != nil {
+			ok, err := o.Confirm(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				results = append(results, r)
+				for _, rs := range rsList {
+					used[rs] = true
+				}
+				continue
+			}
+		}
+		confirmed = append(confirmed, bulkDeleteTarget{index: len(results), certificate: c, recordSets: rsList})
+		results = append(results, r)
+	}
+
+	// Certificates in other regions, such as replicas, can share the records.
+	// They are checked only if any record of the confirmed certificates is not used in this region.
+	domains := []string{}
+	for _, t := range confirmed {
+		for _, rs := range t.recordSets {
+			if !used[rs] {
+				domains = append(append(domains, t.certificate.DomainName), t.certificate.SubjectAlternativeNames...)
+				break
+			}
+		}
+	}
+	if do.Regions != nil && len(domains) > 0 {
+		except := map[string]bool{}
+		for _, t := range confirmed {
+			except[t.certificate.Arn] = true
+		}
+		apis := []ACMAPI{}
+		for r, api := range do.Regions {
+			if r != arnRegion(confirmed[0].certificate.Arn) {
+				apis = append(apis, api)
+			}
+		}
+		other, err := usedValidationRecords(ctx, apis, except, domains)
+		if err != nil {
+			return nil, err
+		}
+		for rs := range other {
+			used[rs] = true
+		}
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, o.Concurrency)
+	)
+	for _, t := range confirmed {
+		wg.Add(1)
+		go func(t bulkDeleteTarget, r *BulkDeleteResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var err error
+			if !do.Force {
+				err = inUseError(t.certificate.Arn, t.certificate.InUseBy)
+			}
+			if err == nil {
+				err = deleteACMCertificate(ctx, aAPI, t.certificate.Arn)
+			}
+			if err != nil {
+				r.Outcome = BulkDeleteOutcomeFailed
+				r.Error = err.Error()
+				return
+			}
+			r.Outcome = BulkDeleteOutcomeDeleted
+		}(t, &results[t.index])
+	}
+	wg.Wait()
+
+	for _, t := range confirmed {
+		if results[t.index].Outcome != BulkDeleteOutcomeDeleted {
+			for _, rs := range t.recordSets {
+				used[rs] = true
+			}
+		}
+	}
+
+	deleted := map[RecordSet]bool{}
+	for _, t := range confirmed {
+		r := &results[t.index]
+		if r.Outcome != BulkDeleteOutcomeDeleted {
+			continue
+		}
+
+		msgs := []string{}
+		for _, rs := range t.recordSets {
+			if used[rs] || deleted[rs] {
+				continue
+			}
+			_, err := deleteRoute53RecordSet(ctx, rAPI, rs)
+			if err != nil && !errors.Is(err, errRecordSetNotFound) {
+				msgs = append(msgs, err.Error())
+				continue
+			}
+			deleted[rs] = true
+		}
+		if len(msgs) > 0 {
+			r.Error = strings.Join(msgs, "; ")
+		}
+	}
+
+	msgs := []string{}
+	for _, r := range results {
+		if r.Error != "" {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", r.CertificateArn, r.Error))
+		}
+	}
+	if len(msgs) > 0 {
+		return results, errors.New(strings.Join(msgs, "; "))
+	}
+
+	return results, nil
+}
+
+// bulkDeleteTarget is a structure that represents a certificate confirmed to be deleted, and the index of its result.
+type bulkDeleteTarget struct {
+	index       int
+	certificate Certificate
+	recordSets  []RecordSet
+}
+
+// Returns true if the certificate matches the filter. The tags are listed only if the filter has tags.
+func bulkDeleteMatch(ctx context.Context, api ACMAPI, filter CertificateFilter, o BulkDeleteOptions, c Certificate) (bool, error) {
+	if !filter.Match(c, o.Now) {
+		return false, nil
+	}
+	if len(filter.Tags) == 0 {
+		return true, nil
+	}
+
+	tags, err := ListCertificateTags(ctx, api, c.Arn)
+	if err != nil {
+		return false, err
+	}
+	return filter.MatchTags(tags), nil
+}
+
+// BulkDeleteCertificates deletes the certificates that match the filter with the ACM and Route 53 clients.
+func (g *GoACM) BulkDeleteCertificates(ctx context.Context, filter CertificateFilter, optFns ...func(*BulkDeleteOptions)) ([]BulkDeleteResult, error) {
+	return BulkDeleteCertificates(ctx, g.ACMAPI(), g.Route53API(), filter, optFns...)
+}
+
+// Returns true if the list contains the string.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
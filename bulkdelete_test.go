@@ -0,0 +1,304 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BulkDeleteCertificates(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/expired",
+				DomainName: "old.example.com",
+				Status:     string(types.CertificateStatusExpired),
+				Type:       string(types.CertificateTypeImported),
+				CreatedAt:  aws.Time(now.AddDate(-1, 0, -1)),
+			},
+			Tags: map[string]string{"env": "dev"},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/timed-out",
+				DomainName: "pending.example.com",
+				Status:     string(types.CertificateStatusValidationTimedOut),
+				Type:       string(types.CertificateTypeAmazonIssued),
+				CreatedAt:  aws.Time(now.AddDate(0, -3, 0)),
+			},
+			Tags: map[string]string{"env": "dev"},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/in-use",
+				DomainName: "used.example.net",
+				Status:     string(types.CertificateStatusExpired),
+				Type:       string(types.CertificateTypeImported),
+				CreatedAt:  aws.Time(now.AddDate(-2, 0, 0)),
+				InUseBy:    []string{"arn:aws:elasticloadbalancing:ap-northeast-1:000000000000:loadbalancer/app/sample/0000000000000000"},
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/issued",
+				DomainName: "www.example.com",
+				Status:     string(types.CertificateStatusIssued),
+				Type:       string(types.CertificateTypeImported),
+				CreatedAt:  aws.Time(now.AddDate(-2, 0, 0)),
+			},
+			Tags: map[string]string{"env": "prod"},
+		},
+	}
+	result := func(i int, outcome goacm.BulkDeleteOutcome) goacm.BulkDeleteResult {
+		return goacm.BulkDeleteResult{
+			CertificateArn: ap[i].Certificate.Arn,
+			DomainName:     ap[i].Certificate.DomainName,
+			Status:         ap[i].Certificate.Status,
+			Outcome:        outcome,
+		}
+	}
+	inUse := result(2, goacm.BulkDeleteOutcomeFailed)
+	inUse.Error = "certificate is in use by " + ap[2].Certificate.InUseBy[0] + ": " + ap[2].Certificate.Arn
+
+	cases := []struct {
+		name          string
+		filter        goacm.CertificateFilter
+		confirm       func(ctx context.Context, c goacm.Certificate) (bool, error)
+		wantErr       bool
+		expect        []goacm.BulkDeleteResult
+		expectDeleted []string
+	}{
+		{
+			name: "normal: by status and not in use",
+			filter: goacm.CertificateFilter{
+				Statuses: []string{string(types.CertificateStatusExpired), string(types.CertificateStatusValidationTimedOut)},
+				NotInUse: true,
+			},
+			expect: []goacm.BulkDeleteResult{
+				result(0, goacm.BulkDeleteOutcomeDeleted),
+				result(1, goacm.BulkDeleteOutcomeDeleted),
+			},
+			expectDeleted: []string{ap[0].Certificate.Arn, ap[1].Certificate.Arn},
+		},
+		{
+			name: "normal: by tag and age",
+			filter: goacm.CertificateFilter{
+				Tags:      map[string]string{"env": "dev"},
+				OlderThan: 365 * 24 * time.Hour,
+			},
+			expect:        []goacm.BulkDeleteResult{result(0, goacm.BulkDeleteOutcomeDeleted)},
+			expectDeleted: []string{ap[0].Certificate.Arn},
+		},
+		{
+			name:   "normal: by domain pattern",
+			filter: goacm.CertificateFilter{DomainPattern: "*.EXAMPLE.com", Statuses: []string{string(types.CertificateStatusIssued)}},
+			expect: []goacm.BulkDeleteResult{
+				result(3, goacm.BulkDeleteOutcomeDeleted),
+			},
+			expectDeleted: []string{ap[3].Certificate.Arn},
+		},
+		{
+			name:   "normal: declined",
+			filter: goacm.CertificateFilter{Tags: map[string]string{"env": "dev"}},
+			confirm: func(ctx context.Context, c goacm.Certificate) (bool, error) {
+				return c.Status == string(types.CertificateStatusExpired), nil
+			},
+			expect: []goacm.BulkDeleteResult{
+				result(0, goacm.BulkDeleteOutcomeDeleted),
+				result(1, goacm.BulkDeleteOutcomeSkipped),
+			},
+			expectDeleted: []string{ap[0].Certificate.Arn},
+		},
+		{
+			name:    "error: in use",
+			filter:  goacm.CertificateFilter{Statuses: []string{string(types.CertificateStatusExpired)}},
+			wantErr: true,
+			expect: []goacm.BulkDeleteResult{
+				result(0, goacm.BulkDeleteOutcomeDeleted),
+				inUse,
+			},
+			expectDeleted: []string{ap[0].Certificate.Arn},
+		},
+		{
+			name:   "error: confirmation failed",
+			filter: goacm.CertificateFilter{Tags: map[string]string{"env": "dev"}},
+			confirm: func(ctx context.Context, c goacm.Certificate) (bool, error) {
+				if c.Status == string(types.CertificateStatusValidationTimedOut) {
+					return false, errors.New("canceled")
+				}
+				return true, nil
+			},
+			wantErr:       true,
+			expectDeleted: []string{},
+		},
+		{
+			name:          "error: empty filter",
+			filter:        goacm.CertificateFilter{},
+			wantErr:       true,
+			expectDeleted: []string{},
+		},
+		{
+			name:          "error: invalid domain pattern",
+			filter:        goacm.CertificateFilter{DomainPattern: "["},
+			wantErr:       true,
+			expectDeleted: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			var (
+				mu      sync.Mutex
+				deleted = []string{}
+			)
+			aAPI := goacm.NewMockACMAPI(ap)
+			del := aAPI.DeleteCertificateAPI
+			aAPI.DeleteCertificateAPI = func(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+				out, err := del(ctx, params, optFns...)
+				if err == nil {
+					mu.Lock()
+					deleted = append(deleted, aws.ToString(params.CertificateArn))
+					mu.Unlock()
+				}
+				return out, err
+			}
+
+			results, err := goacm.BulkDeleteCertificates(context.TODO(), aAPI, goacm.NewMockRoute53API(nil), c.filter, func(o *goacm.BulkDeleteOptions) {
+				o.Concurrency = 2
+				o.Confirm = c.confirm
+				o.Now = now
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			if c.expect != nil {
+				assert.Equal(tt, c.expect, results)
+			}
+			assert.ElementsMatch(tt, c.expectDeleted, deleted)
+		})
+	}
+}
+
+func Test_BulkDeleteCertificates_Records(t *testing.T) {
+	shared := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.shared.example.com",
+		Value:            "_validation.value.shared.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	duplicated := goacm.RecordSet{
+		HostedDomainName: "example.net",
+		Name:             "_validation.name.duplicated.example.net",
+		Value:            "_validation.value.duplicated.example.net",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	certificate := func(name, status string, rs goacm.RecordSet) goacm.MockACMParams {
+		return goacm.MockACMParams{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:ap-northeast-1:000000000000:certificate/" + name,
+				DomainName:          "www." + rs.HostedDomainName,
+				Status:              status,
+				Type:                string(types.CertificateTypeAmazonIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rs,
+			},
+		}
+	}
+	ap := []goacm.MockACMParams{
+		certificate("shared-1", string(types.CertificateStatusExpired), shared),
+		certificate("shared-2", string(types.CertificateStatusExpired), shared),
+		// the issued certificate does not match the filter, and needs the record to be renewed
+		certificate("shared-issued", string(types.CertificateStatusIssued), shared),
+		certificate("duplicated-1", string(types.CertificateStatusExpired), duplicated),
+		certificate("duplicated-2", string(types.CertificateStatusExpired), duplicated),
+	}
+	rp := []goacm.MockRoute53Params{
+		{RecordSet: shared, ChangeAction: route53Types.ChangeActionDelete},
+		{RecordSet: duplicated, ChangeAction: route53Types.ChangeActionDelete},
+	}
+	filter := goacm.CertificateFilter{Statuses: []string{string(types.CertificateStatusExpired)}}
+
+	cases := []struct {
+		name                 string
+		describeErr          string
+		wantErr              bool
+		expectDeleted        []string
+		expectDeletedRecords []string
+	}{
+		{
+			name: "normal",
+			expectDeleted: []string{
+				ap[0].Certificate.Arn,
+				ap[1].Certificate.Arn,
+				ap[3].Certificate.Arn,
+				ap[4].Certificate.Arn,
+			},
+			expectDeletedRecords: []string{duplicated.Name},
+		},
+		{
+			name:                 "error: failed to describe a certificate",
+			describeErr:          ap[2].Certificate.Arn,
+			wantErr:              true,
+			expectDeleted:        []string{},
+			expectDeletedRecords: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			var (
+				mu      sync.Mutex
+				deleted = []string{}
+			)
+			aAPI := goacm.NewMockACMAPI(ap)
+			del := aAPI.DeleteCertificateAPI
+			aAPI.DeleteCertificateAPI = func(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+				mu.Lock()
+				deleted = append(deleted, aws.ToString(params.CertificateArn))
+				mu.Unlock()
+				return del(ctx, params, optFns...)
+			}
+			describe := aAPI.DescribeCertificateAPI
+			aAPI.DescribeCertificateAPI = func(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+				if aws.ToString(params.CertificateArn) == c.describeErr {
+					return nil, errors.New("throttled")
+				}
+				return describe(ctx, params, optFns...)
+			}
+
+			rAPI := goacm.NewMockRoute53API(rp)
+			deletedRecords := []string{}
+			change := rAPI.ChangeResourceRecordSetsAPI
+			rAPI.ChangeResourceRecordSetsAPI = func(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+				deletedRecords = append(deletedRecords, aws.ToString(params.ChangeBatch.Changes[0].ResourceRecordSet.Name))
+				return change(ctx, params, optFns...)
+			}
+
+			_, err := goacm.BulkDeleteCertificates(context.TODO(), aAPI, rAPI, filter, func(o *goacm.BulkDeleteOptions) {
+				o.Concurrency = 4
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.ElementsMatch(tt, c.expectDeleted, deleted)
+			assert.Equal(tt, c.expectDeletedRecords, deletedRecords)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package goacm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// BundleFormat is a type that represents a format of certificate bundles.
+type BundleFormat string
+
+// Formats of certificate bundles.
+const (
+	// BundleFormatPEM is the certificate, the certificate chain and the unencrypted PKCS#8 private key in PEM.
+	BundleFormatPEM BundleFormat = "pem"
+
+	// BundleFormatPKCS12 is a PKCS#12 (.p12 or .pfx) file protected by the password.
+	BundleFormatPKCS12 BundleFormat = "pkcs12"
+
+	// BundleFormatJKS is a Java KeyStore protected by the password.
+	BundleFormatJKS BundleFormat = "jks"
+)
+
+// BundleFormats are the supported formats of certificate bundles.
+var BundleFormats = []BundleFormat{BundleFormatPEM, BundleFormatPKCS12, BundleFormatJKS}
+
+// DefaultBundleFileMode is the default permission of bundle files, that contain the private key.
+const DefaultBundleFileMode os.FileMode = 0600
+
+// DefaultBundleAlias is the default alias of the entry in JKS bundles.
+const DefaultBundleAlias = "certificate"
+
+// BundleOptions is a structure that represents options for Bundle and ExportCertificateBundle.
+type BundleOptions struct {
+	// Format is a format of the bundle. Default is BundleFormatPEM.
+	Format BundleFormat
+
+	// Password protects PKCS#12 and JKS bundles. Default is the passphrase of the exported private key.
+	Password []byte
+
+	// Alias is an alias of the entry in JKS bundles. Default is DefaultBundleAlias.
+	Alias string
+
+	// LegacyPKCS12 encrypts PKCS#12 bundles with the legacy 3DES, for clients that cannot read AES,
+	// such as Java before 8u301 and Windows Server 2016. Default is AES-256 with PBKDF2.
+	LegacyPKCS12 bool
+
+	// FileMode is a permission of the bundle file written by ExportCertificateBundle. Default is DefaultBundleFileMode.
+	FileMode os.FileMode
+}
+
+// Bundle decrypts the private key of the exported certificate with the passphrase,
+// and returns the certificate, the certificate chain and the key in the format.
+// Errors contain neither the passphrase nor the key.
+func (e ExportedCertificate) Bundle(passphrase []byte, optFns ...func(*BundleOptions)) ([]byte, error) {
+	o := BundleOptions{
+		Format: BundleFormatPEM,
+		Alias:  DefaultBundleAlias,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.Password == nil {
+		o.Password = passphrase
+	}
+
+	if err := validateBundleFormat(o.Format); err != nil {
+		return nil, err
+	}
+
+	certs, err := parseCertificates(e.Certificate + "\n" + e.CertificateChain)
+	if err != nil {
+		return nil, err
+	}
+
+	key, der, err := decryptPrivateKey([]byte(e.PrivateKey), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(der)
+
+	switch o.Format {
+	case BundleFormatPKCS12:
+		enc := pkcs12.Modern
+		if o.LegacyPKCS12 {
+			enc = pkcs12.Legacy
+		}
+		b, err := enc.WithRand(rand.Reader).Encode(key, certs[0], certs[1:], string(o.Password))
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode the PKCS#12 bundle: %w", err)
+		}
+		return b, nil
+
+	case BundleFormatJKS:
+		return jksBundle(certs, der, o)
+	}
+
+	var buf bytes.Buffer
+	for _, c := range certs {
+		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}); err != nil {
+			return nil, err
+		}
+	}
+	if err := pem.Encode(&buf, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return nil, errors.New("cannot encode the private key")
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportCertificateBundle exports a private certificate with ExportCertificate,
+// and writes the bundle of the certificate in the format to the file.
+// The file is replaced atomically, and has the permission of the options even if it exists.
+// Errors contain neither the passphrase nor the key.
+func ExportCertificateBundle(ctx context.Context, api ACMExportCertificateAPI, arn string, passphrase []byte, path string, optFns ...func(*BundleOptions)) error {
+	o := BundleOptions{
+		Format:   BundleFormatPEM,
+		FileMode: DefaultBundleFileMode,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if err := validateBundleFormat(o.Format); err != nil {
+		return err
+	}
+
+	e, err := ExportCertificate(ctx, api, arn, passphrase)
+	if err != nil {
+		return err
+	}
+
+	b, err := e.Bundle(passphrase, optFns...)
+	if err != nil {
+		return err
+	}
+	defer zero(b)
+
+	return writeFileAtomic(path, b, o.FileMode)
+}
+
+// Returns an error if the format is not one of BundleFormats.
+func validateBundleFormat(format BundleFormat) error {
+	for _, f := range BundleFormats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported bundle format: %s (supported: pem, pkcs12, jks)", format)
+}
+
+// Returns the certificates in the PEM data. The first is the certificate, and the rest are the chain.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var b *pem.Block
+		b, rest = pem.Decode(rest)
+		if b == nil {
+			break
+		}
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+
+		c, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the exported certificate: %w", err)
+		}
+		certs = append(certs, c)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("exported certificate has no PEM encoded certificate")
+	}
+	return certs, nil
+}
+
+// Returns the Java KeyStore of the private key in PKCS#8 DER and the certificates.
+func jksBundle(certs []*x509.Certificate, der []byte, o BundleOptions) ([]byte, error) {
+	entry := keystore.PrivateKeyEntry{
+		CreationTime: time.Now(),
+		PrivateKey:   der,
+	}
+	for _, c := range certs {
+		entry.CertificateChain = append(entry.CertificateChain, keystore.Certificate{Type: "X509", Content: c.Raw})
+	}
+
+	ks := keystore.New()
+	if err := ks.SetPrivateKeyEntry(o.Alias, entry, o.Password); err != nil {
+		return nil, errors.New("cannot add the private key to the JKS bundle")
+	}
+
+	var buf bytes.Buffer
+	if err := ks.Store(&buf, o.Password); err != nil {
+		return nil, errors.New("cannot encode the JKS bundle")
+	}
+	return buf.Bytes(), nil
+}
+
+// Write the data to a temporary file in the directory of the path, and rename it to the path,
+// so that the file never has partial data nor looser permission.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
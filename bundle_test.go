@@ -0,0 +1,271 @@
+package goacm_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	"github.com/stretchr/testify/assert"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Returns the exported certificate in testdata, whose private key is encrypted with "passphrase"
+// except private_key_many_iterations.pem, encrypted with "many-iterations".
+func testExportedCertificate(t *testing.T, keyFile string) goacm.ExportedCertificate {
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	return goacm.ExportedCertificate{
+		Certificate:      read("certificate.pem"),
+		CertificateChain: read("chain.pem"),
+		PrivateKey:       read(keyFile),
+	}
+}
+
+func Test_ExportedCertificate_Bundle(t *testing.T) {
+	cases := []struct {
+		name       string
+		keyFile    string
+		passphrase string
+		format     goacm.BundleFormat
+		password   string
+		legacy     bool
+		wantErr    bool
+	}{
+		{
+			name:       "normal: PEM",
+			keyFile:    "private_key.pem",
+			passphrase: "passphrase",
+			format:     goacm.BundleFormatPEM,
+		},
+		{
+			name:       "normal: PEM of key encrypted with 3DES",
+			keyFile:    "private_key_des3.pem",
+			passphrase: "passphrase",
+			format:     goacm.BundleFormatPEM,
+		},
+		{
+			name:       "normal: PKCS#12",
+			keyFile:    "private_key.pem",
+			passphrase: "passphrase",
+			format:     goacm.BundleFormatPKCS12,
+		},
+		{
+			name:       "normal: PKCS#12 with password",
+			keyFile:    "private_key.pem",
+			passphrase: "passphrase",
+			format:     goacm.BundleFormatPKCS12,
+			password:   "changeit",
+		},
+		{
+			name:       "normal: legacy PKCS#12",
+			keyFile:    "private_key.pem",
+			passphrase: "passphrase",
+			format:     goacm.BundleFormatPKCS12,
+			legacy:     true,
+		},
+		{
+			name:       "normal: JKS",
+			keyFile:    "private_key.pem",
+			passphrase: "passphrase",
+			format:     goacm.BundleFormatJKS,
+			password:   "changeit",
+		},
+		{
+			name:       "error: incorrect passphrase",
+			keyFile:    "private_key.pem",
+			passphrase: "incorrect-passphrase",
+			format:     goacm.BundleFormatPKCS12,
+			wantErr:    true,
+		},
+		{
+			name:       "error: too many iterations",
+			keyFile:    "private_key_many_iterations.pem",
+			passphrase: "many-iterations",
+			format:     goacm.BundleFormatPEM,
+			wantErr:    true,
+		},
+		{
+			name:       "error: unsupported format",
+			keyFile:    "private_key.pem",
+			passphrase: "passphrase",
+			format:     "der",
+			wantErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			e := testExportedCertificate(tt, c.keyFile)
+			b, err := e.Bundle([]byte(c.passphrase), func(o *goacm.BundleOptions) {
+				o.Format = c.format
+				o.LegacyPKCS12 = c.legacy
+				if c.password != "" {
+					o.Password = []byte(c.password)
+				}
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.NotContains(tt, err.Error(), c.passphrase)
+				assert.NotContains(tt, err.Error(), "PRIVATE KEY")
+				assert.Nil(tt, b)
+				return
+			}
+
+			assert.NoError(tt, err)
+
+			password := c.password
+			if password == "" {
+				password = c.passphrase
+			}
+
+			switch c.format {
+			case goacm.BundleFormatPEM:
+				var blocks []string
+				var key interface{}
+				for rest := b; ; {
+					var p *pem.Block
+					p, rest = pem.Decode(rest)
+					if p == nil {
+						break
+					}
+					blocks = append(blocks, p.Type)
+					if p.Type == "PRIVATE KEY" {
+						key, err = x509.ParsePKCS8PrivateKey(p.Bytes)
+						assert.NoError(tt, err)
+					}
+				}
+				assert.Equal(tt, []string{"CERTIFICATE", "CERTIFICATE", "PRIVATE KEY"}, blocks)
+				assert.NotNil(tt, key)
+
+			case goacm.BundleFormatPKCS12:
+				key, cert, chain, err := pkcs12.DecodeChain(b, password)
+				assert.NoError(tt, err)
+				assert.NotNil(tt, key)
+				assert.Equal(tt, "internal.example.com", cert.Subject.CommonName)
+				assert.Len(tt, chain, 1)
+
+			case goacm.BundleFormatJKS:
+				ks := keystore.New()
+				assert.NoError(tt, ks.Load(bytes.NewReader(b), []byte(password)))
+				entry, err := ks.GetPrivateKeyEntry(goacm.DefaultBundleAlias, []byte(password))
+				assert.NoError(tt, err)
+				assert.Len(tt, entry.CertificateChain, 2)
+				_, err = x509.ParsePKCS8PrivateKey(entry.PrivateKey)
+				assert.NoError(tt, err)
+			}
+		})
+	}
+}
+
+func Test_ExportCertificateBundle(t *testing.T) {
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:  "arn:aws:acm:ap-northeast-1:000000000000:certificate/private",
+				Type: string(types.CertificateTypePrivate),
+			},
+			ExportedCertificate: testExportedCertificate(t, "private_key.pem"),
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:  "arn:aws:acm:ap-northeast-1:000000000000:certificate/public",
+				Type: string(types.CertificateTypeAmazonIssued),
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		arn        string
+		passphrase string
+		mode       os.FileMode
+		exists     bool
+		wantErr    bool
+		expectMode os.FileMode
+	}{
+		{
+			name:       "normal",
+			arn:        ap[0].Certificate.Arn,
+			passphrase: "passphrase",
+			expectMode: 0600,
+		},
+		{
+			name:       "normal: file mode",
+			arn:        ap[0].Certificate.Arn,
+			passphrase: "passphrase",
+			mode:       0640,
+			expectMode: 0640,
+		},
+		{
+			name:       "normal: replace existing file",
+			arn:        ap[0].Certificate.Arn,
+			passphrase: "passphrase",
+			exists:     true,
+			expectMode: 0600,
+		},
+		{
+			name:       "error: not a private certificate",
+			arn:        ap[1].Certificate.Arn,
+			passphrase: "passphrase",
+			wantErr:    true,
+		},
+		{
+			name:       "error: incorrect passphrase",
+			arn:        ap[0].Certificate.Arn,
+			passphrase: "incorrect-passphrase",
+			wantErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			path := filepath.Join(tt.TempDir(), "bundle.p12")
+			if c.exists {
+				if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+					tt.Fatal(err)
+				}
+			}
+
+			err := goacm.ExportCertificateBundle(context.TODO(), goacm.NewMockACMAPI(ap), c.arn, []byte(c.passphrase), path, func(o *goacm.BundleOptions) {
+				o.Format = goacm.BundleFormatPKCS12
+				if c.mode != 0 {
+					o.FileMode = c.mode
+				}
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.NotContains(tt, err.Error(), c.passphrase)
+				_, statErr := os.Stat(path)
+				assert.True(tt, os.IsNotExist(statErr))
+				return
+			}
+
+			assert.NoError(tt, err)
+			fi, err := os.Stat(path)
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expectMode, fi.Mode().Perm())
+
+			b, err := os.ReadFile(path)
+			assert.NoError(tt, err)
+			_, _, _, err = pkcs12.DecodeChain(b, c.passphrase)
+			assert.NoError(tt, err)
+
+			files, err := os.ReadDir(filepath.Dir(path))
+			assert.NoError(tt, err)
+			assert.Len(tt, files, 1)
+		})
+	}
+}
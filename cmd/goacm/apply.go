@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/manifest"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "apply",
+		summary: "Issue, retag and delete certificates to match a manifest file.",
+		usage:   "-f file [-prune] [-wait] [-dry-run] [-output format]",
+		run:     runApply,
+	})
+}
+
+func runApply(ctx context.Context, a *app, args []string) error {
+	var (
+		cf     clientFlags
+		of     outputFlags
+		file   string
+		prune  bool
+		wait   bool
+		dryRun bool
+	)
+	fs := a.flagSet(commands["apply"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.StringVar(&file, "f", "", "Manifest file in YAML or JSON.")
+	fs.BoolVar(&prune, "prune", false, "Delete certificates owned by the manifest that are no longer in it.")
+	fs.BoolVar(&wait, "wait", false, "Wait until the issued certificates are issued.")
+	fs.BoolVar(&dryRun, "dry-run", false, "Show the changes without making them.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	if file == "" {
+		return usageError{msg: "-f is required"}
+	}
+
+	m, err := manifest.Load(file)
+	if err != nil {
+		var errs manifest.Errors
+		if errors.As(err, &errs) {
+			return usageError{msg: "invalid manifest:\n" + errs.Error()}
+		}
+		return err
+	}
+
+	mg, err := cf.newMultiRegionGoACM(ctx, m.Regions())
+	if err != nil {
+		return err
+	}
+
+	res, err := manifest.Apply(ctx, m, mg.ACMAPIs(), mg.Route53API(), func(o *goacm.ReconcileOptions) {
+		o.Prune = prune
+		o.Wait = wait
+		o.DryRun = dryRun
+	})
+	var errs manifest.Errors
+	if errors.As(err, &errs) {
+		return usageError{msg: "invalid manifest:\n" + errs.Error()}
+	}
+	if res.Changes != nil {
+		if rerr := of.reconcileResult(a, res); rerr != nil {
+			return rerr
+		}
+	}
+	return err
+}
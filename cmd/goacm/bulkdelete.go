@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "bulk-delete",
+		summary: "Delete the certificates that match the filter with the records that validate the domains.",
+		usage:   "[-status s1,s2] [-older-than duration] [-tags k1=v1,k2=v2] [-domain pattern] [-not-in-use] [-concurrency n] [-force] [-regions r1,r2|all] [-yes] [-output format]",
+		run:     runBulkDelete,
+	})
+}
+
+// bulkDeleteColumns are default columns of the results of bulk delete.
+var bulkDeleteColumns = []string{"outcome", "status", "domainName", "certificateArn", "error"}
+
+func runBulkDelete(ctx context.Context, a *app, args []string) error {
+	var (
+		cf          clientFlags
+		of          outputFlags
+		filter      goacm.CertificateFilter
+		statuses    string
+		tags        string
+		concurrency int
+		force       bool
+		regions     string
+		yes         bool
+	)
+	fs := a.flagSet(commands["bulk-delete"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.StringVar(&statuses, "status", "", "Comma separated statuses such as EXPIRED,VALIDATION_TIMED_OUT.")
+	fs.DurationVar(&filter.OlderThan, "older-than", 0, "Delete certificates created more than the duration ago.")
+	fs.StringVar(&tags, "tags", "", "Comma separated key=value tags that certificates must have.")
+	fs.StringVar(&filter.DomainPattern, "domain", "", `Shell pattern of domain names such as "*.example.com".`)
+	fs.BoolVar(&filter.NotInUse, "not-in-use", false, "Delete only certificates that are not in use.")
+	fs.IntVar(&concurrency, "concurrency", goacm.DefaultBulkDeleteConcurrency, "Number of certificates to delete at once.")
+	fs.BoolVar(&force, "force", false, "Try to delete certificates that are listed as in use, after detaching them from the resources.")
+	fs.StringVar(&regions, "regions", "", `Comma separated regions, or "all", whose certificates can share the validation records, such as replicas.`)
+	fs.BoolVar(&yes, "yes", false, "Delete without confirmation of each certificate.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	filter.Statuses = splitList(statuses)
+	t, err := parseTags(tags)
+	if err != nil {
+		return err
+	}
+	if len(t) > 0 {
+		filter.Tags = t
+	}
+	if filter.IsEmpty() {
+		return usageError{msg: "at least one of -status, -older-than, -tags, -domain and -not-in-use is required"}
+	}
+	if err := filter.Validate(); err != nil {
+		return usageError{msg: err.Error()}
+	}
+	if concurrency <= 0 {
+		return usageError{msg: "-concurrency must be positive"}
+	}
+
+	g, err := cf.newGoACM(ctx)
+	if err != nil {
+		return err
+	}
+	var apis goacm.RegionalACMAPI
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+		apis = m.ACMAPIs()
+	}
+
+	results, err := g.BulkDeleteCertificates(ctx, filter, func(o *goacm.BulkDeleteOptions) {
+		o.Concurrency = concurrency
+		if !yes {
+			o.Confirm = a.confirmDelete(bufio.NewReader(a.stdin))
+		}
+		o.Delete = append(o.Delete, func(o *goacm.DeleteCertificateOptions) {
+			o.Force = force
+			o.Regions = apis
+		})
+	})
+	if results != nil {
+		if rerr := of.items(a, results, bulkDeleteColumns); rerr != nil {
+			return rerr
+		}
+	}
+	return err
+}
+
+// Returns a function that asks whether to delete each certificate, and reads the answer from r.
+func (a *app) confirmDelete(r *bufio.Reader) func(ctx context.Context, c goacm.Certificate) (bool, error) {
+	return func(ctx context.Context, c goacm.Certificate) (bool, error) {
+		fmt.Fprintf(a.stderr, "delete %s (%s, created at %s) %s? [y/N] ", c.DomainName, c.Status, formatTime(c.CreatedAt), c.Arn)
+		answer, err := r.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		}
+		return false, nil
+	}
+}
+
+// Returns the time in RFC 3339, or "-" if it is nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/michimani/goacm"
+)
+
+// clientFlags is a structure that represents flags to create goacm clients.
+type clientFlags struct {
+	region         string
+	profile        string
+	roleArn        string
+	route53RoleArn string
+	externalID     string
+}
+
+func (f *clientFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.region, "region", "", "AWS region. Defaults to the region of the ARN or the shared config.")
+	fs.StringVar(&f.profile, "profile", "", "Name of the shared config profile.")
+	fs.StringVar(&f.roleArn, "role-arn", "", "ARN of the IAM role to assume for ACM and Route 53.")
+	fs.StringVar(&f.route53RoleArn, "route53-role-arn", "", "ARN of the IAM role to assume only for Route 53.")
+	fs.StringVar(&f.externalID, "external-id", "", "External ID used in assuming the roles.")
+}
+
+func (f *clientFlags) options() []func(*goacm.GoACMOptions) {
+	var optFns []func(*goacm.GoACMOptions)
+	if f.profile != "" {
+		optFns = append(optFns, goacm.WithProfile(f.profile))
+	}
+	if f.roleArn != "" {
+		optFns = append(optFns, goacm.WithAssumeRole(goacm.AssumeRole{RoleArn: f.roleArn, ExternalID: f.externalID}))
+	}
+	if f.route53RoleArn != "" {
+		optFns = append(optFns, goacm.WithRoute53AssumeRole(goacm.AssumeRole{RoleArn: f.route53RoleArn, ExternalID: f.externalID}))
+	}
+	return optFns
+}
+
+// Returns a GoACM of the region flag.
+func (f *clientFlags) newGoACM(ctx context.Context) (*goacm.GoACM, error) {
+	return goacm.NewGoACM(ctx, f.region, f.options()...)
+}
+
+// Returns a GoACM of the region flag, or the region of the certificate ARN if the flag is not set.
+func (f *clientFlags) newGoACMForArn(ctx context.Context, certificateArn string) (*goacm.GoACM, error) {
+	region := f.region
+	if region == "" {
+		a, err := arn.Parse(certificateArn)
+		if err != nil {
+			return nil, usageError{msg: err.Error()}
+		}
+		region = a.Region
+	}
+	return goacm.NewGoACM(ctx, region, f.options()...)
+}
+
+// Returns a MultiRegionGoACM of the regions. "all" means the regions enabled for the account,
+// or goacm.ACMRegions if they cannot be listed.
+func (f *clientFlags) newMultiRegionGoACM(ctx context.Context, regions []string) (*goacm.MultiRegionGoACM, error) {
+	if len(regions) == 1 && regions[0] == "all" {
+		regions = nil
+	}
+	return goacm.NewMultiRegionGoACM(ctx, regions, f.options()...)
+}
+
+// Returns the list of a comma separated string.
+func splitList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// Returns the first argument as a certificate ARN.
+func arnArg(fs *flag.FlagSet) (string, error) {
+	if fs.NArg() != 1 {
+		return "", usageError{msg: "exactly one certificate ARN is required"}
+	}
+	return fs.Arg(0), nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+)
+
+// Exit codes of the command.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitNotFound
+	exitInUse
+	exitCertificateStatus
+	exitRoute53
+	exitPartial
+)
+
+// usageError is an error that represents the command is used incorrectly.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// Returns the exit code for the error.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var (
+		ue  usageError
+		cse *goacm.CertificateStatusError
+		cie *goacm.CertificateInUseError
+		re  *goacm.RegionsError
+		ce  *goacm.CertificatesError
+		se  *goacm.ServiceError
+		nfe *acmTypes.ResourceNotFoundException
+		iue *acmTypes.ResourceInUseException
+		iae *acmTypes.InvalidArnException
+		ipe *acmTypes.InvalidParameterException
+		ide *acmTypes.InvalidDomainValidationOptionsException
+		ve  *acmTypes.ValidationException
+	)
+
+	switch {
+	case errors.As(err, &ue), errors.As(err, &iae), errors.As(err, &ipe), errors.As(err, &ide), errors.As(err, &ve):
+		return exitUsage
+	case errors.As(err, &cse):
+		return exitCertificateStatus
+	case errors.As(err, &nfe):
+		return exitNotFound
+	case errors.As(err, &cie), errors.As(err, &iue):
+		return exitInUse
+	case errors.As(err, &re), errors.As(err, &ce):
+		return exitPartial
+	case errors.As(err, &se) && se.Service == goacm.ServiceRoute53:
+		return exitRoute53
+	}
+
+	return exitError
+}
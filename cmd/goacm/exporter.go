@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/exporter"
+)
+
+func init() {
+	register(command{
+		name:    "exporter",
+		summary: "Serve metrics of certificates for Prometheus.",
+		usage:   "[-listen address] [-path path] [-regions r1,r2|all] [-cache-ttl duration] [-interval duration]",
+		run:     runExporter,
+	})
+}
+
+func runExporter(ctx context.Context, a *app, args []string) error {
+	var (
+		cf       clientFlags
+		listen   string
+		path     string
+		regions  string
+		cacheTTL time.Duration
+		interval time.Duration
+	)
+	fs := a.flagSet(commands["exporter"])
+	cf.register(fs)
+	fs.StringVar(&listen, "listen", ":9732", "Address to listen on.")
+	fs.StringVar(&path, "path", "/metrics", "Path of the metrics.")
+	fs.StringVar(&regions, "regions", "", `Comma separated regions to list, or "all". Defaults to the region flag.`)
+	fs.DurationVar(&cacheTTL, "cache-ttl", exporter.DefaultCacheTTL, "Duration for which listed certificates are cached.")
+	fs.DurationVar(&interval, "interval", 0, "Interval to refresh certificates in the background. Defaults to refreshing on scrapes after the cache TTL.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if cacheTTL <= 0 || interval < 0 {
+		return usageError{msg: "-cache-ttl must be positive, and -interval must not be negative"}
+	}
+
+	var apis goacm.RegionalACMAPI
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+		apis = m.ACMAPIs()
+	} else {
+		g, err := cf.newGoACM(ctx)
+		if err != nil {
+			return err
+		}
+		apis = goacm.RegionalACMAPI{g.Region: g.ACMAPI()}
+	}
+
+	e := exporter.New(apis, func(o *exporter.Options) {
+		o.CacheTTL = cacheTTL
+	})
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if interval > 0 {
+		go e.Run(ctx, interval, func(err error) {
+			fmt.Fprintln(a.stderr, err)
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, e)
+	srv := &http.Server{Addr: listen, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(a.stderr, "serving metrics on %s%s\n", listen, path)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "issue",
+		summary: "Issue a certificate, and create the record that validates the domain.",
+		usage:   "-domain name (-hosted-domain name [-method DNS|EMAIL] | -ca-arn arn) [-key-algorithm name] [-transparency-logging ENABLED|DISABLED] [-wait] [-regions r1,r2] [-dry-run] [-output format]",
+		run:     runIssue,
+	})
+	register(command{
+		name:    "delete",
+		summary: "Delete certificates with the records that validate the domains.",
+		usage:   "[-dry-run] [-force] [-regions r1,r2|all] <certificate-arn>...",
+		run:     runDelete,
+	})
+	register(command{
+		name:    "update",
+		summary: "Update the certificate transparency logging preference of a certificate.",
+		usage:   "-transparency-logging ENABLED|DISABLED <certificate-arn>",
+		run:     runUpdate,
+	})
+	register(command{
+		name:    "wait",
+		summary: "Wait until a certificate is issued.",
+		usage:   "[-timeout duration] [-interval duration] <certificate-arn>",
+		run:     runWait,
+	})
+	register(command{
+		name:    "resend-email",
+		summary: "Resend the validation emails of a certificate pending validation by email.",
+		usage:   "[-output format] <certificate-arn>",
+		run:     runResendEmail,
+	})
+}
+
+// validationEmailColumns are default columns of validation emails.
+var validationEmailColumns = []string{"domainName", "validationDomain", "validationEmails", "error"}
+
+func runIssue(ctx context.Context, a *app, args []string) error {
+	var (
+		cf           clientFlags
+		of           outputFlags
+		domain       string
+		hostedDomain string
+		method       string
+		wait         bool
+		regions      string
+		dryRun       bool
+		keyAlgorithm string
+		transparency string
+		token        string
+		caArn        string
+	)
+	fs := a.flagSet(commands["issue"])
+	cf.register(fs)
+	of.register(fs, render.FormatYAML)
+	fs.StringVar(&domain, "domain", "", "Domain name of the certificate.")
+	fs.StringVar(&hostedDomain, "hosted-domain", "", "Domain name of the public hosted zone that validates the domain.")
+	fs.StringVar(&method, "method", string(acmTypes.ValidationMethodDns), "Validation method, DNS or EMAIL.")
+	fs.BoolVar(&wait, "wait", false, "Wait until the certificate is issued.")
+	fs.StringVar(&regions, "regions", "", "Comma separated regions to replicate the certificate into. It always waits.")
+	fs.BoolVar(&dryRun, "dry-run", false, "Show the changes to ACM and Route 53 without making them.")
+	fs.StringVar(&keyAlgorithm, "key-algorithm", "", "Key algorithm of the certificate, RSA_2048, EC_prime256v1 or EC_secp384r1. Default is RSA_2048.")
+	fs.StringVar(&transparency, "transparency-logging", "", "Certificate transparency logging preference, ENABLED or DISABLED. Default is ENABLED.")
+	fs.StringVar(&token, "idempotency-token", "", "Token that makes retried requests return the same certificate. Default is derived from the request.")
+	fs.StringVar(&caArn, "ca-arn", "", "ARN of the AWS Private CA that issues a private certificate. Route 53 is not used.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	if caArn != "" {
+		if domain == "" {
+			return usageError{msg: "-domain is required"}
+		}
+		if hostedDomain != "" {
+			return usageError{msg: "-hosted-domain cannot be used with -ca-arn"}
+		}
+		if regions != "" || dryRun {
+			return usageError{msg: "-regions and -dry-run cannot be used with -ca-arn"}
+		}
+	} else if domain == "" || hostedDomain == "" {
+		return usageError{msg: "-domain and -hosted-domain are required"}
+	}
+	if method != string(acmTypes.ValidationMethodDns) && method != string(acmTypes.ValidationMethodEmail) {
+		return usageError{msg: fmt.Sprintf("invalid validation method: %s", method)}
+	}
+
+	if err := goacm.ValidateKeyAlgorithm(keyAlgorithm); err != nil {
+		return usageError{msg: err.Error()}
+	}
+	if err := goacm.ValidateTransparencyLogging(transparency); err != nil {
+		return usageError{msg: err.Error()}
+	}
+	issueOptions := func(o *goacm.IssueCertificateOptions) {
+		o.KeyAlgorithm = keyAlgorithm
+		o.TransparencyLogging = transparency
+		o.IdempotencyToken = token
+	}
+
+	if dryRun && regions != "" {
+		return usageError{msg: "-dry-run cannot be used with -regions"}
+	}
+
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+
+		res, err := m.ReplicateCertificate(ctx, method, domain, hostedDomain, func(o *goacm.ReplicateCertificateOptions) {
+			o.IssueOptions = []func(*goacm.IssueCertificateOptions){issueOptions}
+		})
+		if err != nil {
+			return err
+		}
+
+		return of.items(a, res.Regions, regionalColumns)
+	}
+
+	g, err := cf.newGoACM(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		plan, err := g.PlanIssueCertificate(ctx, method, domain, hostedDomain, issueOptions)
+		if err != nil {
+			return err
+		}
+		return of.plan(a, plan)
+	}
+
+	var res goacm.IssueCertificateResult
+	if caArn != "" {
+		res, err = g.IssuePrivateCertificate(ctx, caArn, domain, issueOptions)
+	} else {
+		res, err = g.IssueCertificate(ctx, method, domain, hostedDomain, issueOptions)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !wait {
+		return of.issueCertificateResult(a, res)
+	}
+
+	c, err := goacm.WaitCertificateIssued(ctx, g.ACMAPI(), res.CertificateArn)
+	if err != nil {
+		return err
+	}
+
+	return of.certificate(a, c)
+}
+
+func runDelete(ctx context.Context, a *app, args []string) error {
+	var (
+		cf      clientFlags
+		of      outputFlags
+		dryRun  bool
+		force   bool
+		regions string
+	)
+	fs := a.flagSet(commands["delete"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.BoolVar(&dryRun, "dry-run", false, "Show the changes to ACM and Route 53 without making them.")
+	fs.BoolVar(&force, "force", false, "Try to delete certificates that are listed as in use, after detaching them from the resources.")
+	fs.StringVar(&regions, "regions", "", `Comma separated regions, or "all", whose certificates can share the validation records, such as replicas. Defaults to the region of each certificate.`)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return usageError{msg: "at least one certificate ARN is required"}
+	}
+	var apis goacm.RegionalACMAPI
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+		apis = m.ACMAPIs()
+	}
+	deleteOptions := func(o *goacm.DeleteCertificateOptions) {
+		o.Force = force
+		o.Regions = apis
+	}
+
+	plan := goacm.Plan{Changes: []goacm.PlannedChange{}}
+	for _, certificateArn := range fs.Args() {
+		g, err := cf.newGoACMForArn(ctx, certificateArn)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			p, err := g.PlanDeleteCertificate(ctx, certificateArn, deleteOptions)
+			if err != nil {
+				return err
+			}
+			plan.Changes = append(plan.Changes, p.Changes...)
+			continue
+		}
+
+		log, err := g.DeleteCertificateWithLog(ctx, certificateArn, deleteOptions)
+		if err != nil {
+			for _, s := range log.Steps {
+				if s.Action != goacm.DeleteActionRestore {
+					continue
+				}
+				if s.Error != "" {
+					fmt.Fprintf(a.stderr, "not restored\t%s\t%s\n", s.RecordSet.Name, s.Error)
+					continue
+				}
+				fmt.Fprintf(a.stderr, "restored\t%s\n", s.RecordSet.Name)
+			}
+			return err
+		}
+
+		fmt.Fprintf(a.stdout, "deleted\t%s\n", certificateArn)
+	}
+
+	if dryRun {
+		return of.plan(a, plan)
+	}
+	return nil
+}
+
+func runUpdate(ctx context.Context, a *app, args []string) error {
+	var (
+		cf           clientFlags
+		transparency string
+	)
+	fs := a.flagSet(commands["update"])
+	cf.register(fs)
+	fs.StringVar(&transparency, "transparency-logging", "", "Certificate transparency logging preference, ENABLED or DISABLED.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if transparency == "" {
+		return usageError{msg: "-transparency-logging must be ENABLED or DISABLED"}
+	}
+	if err := goacm.ValidateTransparencyLogging(transparency); err != nil {
+		return usageError{msg: err.Error()}
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	if err := g.UpdateCertificateTransparencyLogging(ctx, certificateArn, transparency); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "updated\t%s\n", certificateArn)
+	return nil
+}
+
+func runWait(ctx context.Context, a *app, args []string) error {
+	var (
+		cf       clientFlags
+		timeout  time.Duration
+		interval time.Duration
+	)
+	fs := a.flagSet(commands["wait"])
+	cf.register(fs)
+	fs.DurationVar(&timeout, "timeout", time.Hour, "Maximum time to wait.")
+	fs.DurationVar(&interval, "interval", 15*time.Second, "Interval of polling.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	c, err := goacm.WaitCertificateIssued(ctx, g.ACMAPI(), certificateArn, func(o *goacm.WaitCertificateOptions) {
+		o.Timeout = timeout
+		o.Interval = interval
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "%s\t%s\n", c.Status, c.Arn)
+	return nil
+}
+
+func runResendEmail(ctx context.Context, a *app, args []string) error {
+	var (
+		cf clientFlags
+		of outputFlags
+	)
+	fs := a.flagSet(commands["resend-email"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	sent, err := g.ResendValidationEmail(ctx, certificateArn)
+	if sent != nil {
+		if rerr := of.items(a, sent, validationEmailColumns); rerr != nil {
+			return rerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "janitor",
+		summary: "Delete certificates pending validation, or timed out with -include-timed-out, for too long with the records that only they use.",
+		usage:   "[-pending-for duration] [-include-timed-out] [-regions r1,r2|all] [-dry-run] [-interval duration] [-output format]",
+		run:     runJanitor,
+	})
+}
+
+// staleCertificateColumns are default columns of stale certificates.
+var staleCertificateColumns = []string{"region", "status", "domainName", "createdAt", "deleted", "certificateArn", "error"}
+
+func runJanitor(ctx context.Context, a *app, args []string) error {
+	var (
+		cf         clientFlags
+		of         outputFlags
+		pendingFor time.Duration
+		regions    string
+		timedOut   bool
+		dryRun     bool
+		interval   time.Duration
+	)
+	fs := a.flagSet(commands["janitor"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.DurationVar(&pendingFor, "pending-for", goacm.DefaultStalePendingAge, "Age after which certificates pending validation are deleted.")
+	fs.BoolVar(&timedOut, "include-timed-out", false, "Also delete certificates that have timed out in validation for longer than -pending-for.")
+	fs.StringVar(&regions, "regions", "", `Comma separated regions to clean, or "all". Defaults to the region flag.`)
+	fs.BoolVar(&dryRun, "dry-run", false, "Show the stale certificates without deleting them.")
+	fs.DurationVar(&interval, "interval", 0, "Interval to clean repeatedly until interrupted. Defaults to cleaning once.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+	if pendingFor <= 0 || interval < 0 {
+		return usageError{msg: "-pending-for must be positive, and -interval must not be negative"}
+	}
+
+	var clean func(ctx context.Context, optFns ...func(*goacm.JanitorOptions)) ([]goacm.StaleCertificate, error)
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+		clean = m.CleanStalePendingCertificates
+	} else {
+		g, err := cf.newGoACM(ctx)
+		if err != nil {
+			return err
+		}
+		clean = g.CleanStalePendingCertificates
+	}
+
+	run := func(ctx context.Context) error {
+		stale, err := clean(ctx, func(o *goacm.JanitorOptions) {
+			o.PendingFor = pendingFor
+			o.IncludeTimedOut = timedOut
+			o.DryRun = dryRun
+		})
+		if stale != nil {
+			if rerr := of.items(a, stale, staleCertificateColumns); rerr != nil {
+				return rerr
+			}
+		}
+		return err
+	}
+
+	if interval == 0 {
+		return run(ctx)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// errors are reported, and the next run retries
+		if err := run(ctx); err != nil {
+			fmt.Fprintln(a.stderr, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
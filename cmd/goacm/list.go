@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "list",
+		summary: "List certificates.",
+		usage:   "[-regions r1,r2|all] [-search pattern] [-output format] [-columns c1,c2] [-sort column] [-desc]",
+		run:     runList,
+	})
+	register(command{
+		name:    "get",
+		summary: "Show the details of a certificate.",
+		usage:   "[-output format] [-columns c1,c2] <certificate-arn>",
+		run:     runGet,
+	})
+	register(command{
+		name:    "expiry",
+		summary: "List certificates that have expired, expire soon or will not be renewed, in order of urgency.",
+		usage:   "[-regions r1,r2|all] [-within duration] [-all] [-output format] [-columns c1,c2]",
+		run:     runExpiry,
+	})
+}
+
+// expiryColumns are default columns of certificates classified by their expiry.
+var expiryColumns = []string{"class", "daysLeft", "certificate.domainName", "certificate.notAfter", "certificate.region", "certificate.arn"}
+
+func runList(ctx context.Context, a *app, args []string) error {
+	var (
+		cf      clientFlags
+		of      outputFlags
+		regions string
+		search  string
+	)
+	fs := a.flagSet(commands["list"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.StringVar(&regions, "regions", "", `Comma separated regions to list concurrently, or "all".`)
+	fs.StringVar(&search, "search", "", `Shell pattern of the domain name such as "*.example.com".`)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	var (
+		cList []goacm.Certificate
+		err   error
+	)
+	if regions != "" {
+		m, merr := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if merr != nil {
+			return merr
+		}
+		if search != "" {
+			cList, err = m.SearchCertificates(ctx, search)
+		} else {
+			cList, err = m.ListCertificates(ctx)
+		}
+	} else {
+		g, gerr := cf.newGoACM(ctx)
+		if gerr != nil {
+			return gerr
+		}
+		apis := goacm.RegionalACMAPI{g.Region: g.ACMAPI()}
+		if search != "" {
+			cList, err = goacm.SearchCertificatesInRegions(ctx, apis, search)
+		} else {
+			cList, err = goacm.ListCertificatesInRegions(ctx, apis)
+		}
+	}
+
+	// print certificates of the succeeded regions even if some regions failed
+	if rerr := of.certificates(a, cList); rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+func runGet(ctx context.Context, a *app, args []string) error {
+	var (
+		cf clientFlags
+		of outputFlags
+	)
+	fs := a.flagSet(commands["get"])
+	cf.register(fs)
+	of.register(fs, render.FormatYAML)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	c, err := goacm.GetCertificate(ctx, g.ACMAPI(), certificateArn)
+	if err != nil {
+		return err
+	}
+	c.Region = g.Region
+
+	return of.certificate(a, c)
+}
+
+func runExpiry(ctx context.Context, a *app, args []string) error {
+	var (
+		cf      clientFlags
+		of      outputFlags
+		regions string
+		within  time.Duration
+		all     bool
+	)
+	fs := a.flagSet(commands["expiry"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.StringVar(&regions, "regions", "", `Comma separated regions to list concurrently, or "all".`)
+	fs.DurationVar(&within, "within", goacm.DefaultExpiryWindow, "Window in which certificates are expiring soon.")
+	fs.BoolVar(&all, "all", false, "Include healthy certificates.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+	if within <= 0 {
+		return usageError{msg: "-within must be positive"}
+	}
+
+	var (
+		cList []goacm.Certificate
+		err   error
+	)
+	if regions != "" {
+		m, merr := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if merr != nil {
+			return merr
+		}
+		cList, err = m.ListCertificates(ctx)
+	} else {
+		g, gerr := cf.newGoACM(ctx)
+		if gerr != nil {
+			return gerr
+		}
+		cList, err = goacm.ListCertificatesInRegions(ctx, goacm.RegionalACMAPI{g.Region: g.ACMAPI()})
+	}
+
+	expiries := []goacm.CertificateExpiry{}
+	for _, e := range goacm.ClassifyCertificateExpiry(cList, func(o *goacm.ExpiryOptions) { o.Window = within }) {
+		if all || e.Class != goacm.ExpiryClassHealthy {
+			expiries = append(expiries, e)
+		}
+	}
+
+	// print certificates of the succeeded regions even if some regions failed
+	if rerr := of.items(a, expiries, expiryColumns); rerr != nil {
+		return rerr
+	}
+	return err
+}
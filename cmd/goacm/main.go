@@ -0,0 +1,122 @@
+// Command goacm is a command line tool for AWS Certificate Manager built on the goacm package.
+//
+// Usage:
+//
+//	goacm <command> [flags] [arguments]
+//
+// Run "goacm help" to see the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a structure that represents a subcommand.
+type command struct {
+	name    string
+	summary string
+	usage   string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+// app is a structure that holds the state shared by subcommands.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+var commands = map[string]command{}
+
+func register(c command) {
+	commands[c.name] = c
+}
+
+func main() {
+	a := &app{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	os.Exit(a.run(context.Background(), os.Args[1:]))
+}
+
+// run runs the subcommand of args, and returns the exit code.
+func (a *app) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		a.printUsage()
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		a.printUsage()
+		return exitOK
+	}
+
+	c, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(a.stderr, "goacm: unknown command %q\n", name)
+		a.printUsage()
+		return exitUsage
+	}
+
+	err := c.run(ctx, a, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(a.stderr, "goacm %s: %v\n", name, err)
+	}
+
+	return exitCode(err)
+}
+
+func (a *app) printUsage() {
+	fmt.Fprintln(a.stderr, "Usage: goacm <command> [flags] [arguments]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		fmt.Fprintf(a.stderr, "  %-10s %s\n", n, commands[n].summary)
+	}
+
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, `Run "goacm <command> -h" to see the flags of the command.`)
+}
+
+// Returns a new flag set of the command.
+func (a *app) flagSet(c command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: goacm %s %s\n\n%s\n\nFlags:\n", c.name, c.usage, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// Parse the flags, and return a usage error if it fails.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_run(t *testing.T) {
+	cases := []struct {
+		name      string
+		args      []string
+		env       map[string]string
+		expect    int
+		expectErr string
+	}{
+		{
+			name:   "normal: help",
+			args:   []string{"help"},
+			expect: exitOK,
+		},
+		{
+			name:   "normal: help of command",
+			args:   []string{"list", "-h"},
+			expect: exitOK,
+		},
+		{
+			name:   "error: no command",
+			args:   []string{},
+			expect: exitUsage,
+		},
+		{
+			name:      "error: unknown command",
+			args:      []string{"unknown"},
+			expect:    exitUsage,
+			expectErr: `unknown command "unknown"`,
+		},
+		{
+			name:      "error: unknown flag",
+			args:      []string{"list", "-unknown"},
+			expect:    exitUsage,
+			expectErr: "flag provided but not defined",
+		},
+		{
+			name:      "error: missing domain",
+			args:      []string{"issue", "-hosted-domain", "example.com"},
+			expect:    exitUsage,
+			expectErr: "-domain and -hosted-domain are required",
+		},
+		{
+			name:      "error: hosted domain with CA",
+			args:      []string{"issue", "-domain", "internal.example.com", "-hosted-domain", "example.com", "-ca-arn", "arn:aws:acm-pca:ap-northeast-1:000000000000:certificate-authority/internal-ca"},
+			expect:    exitUsage,
+			expectErr: "-hosted-domain cannot be used with -ca-arn",
+		},
+		{
+			name:      "error: invalid method",
+			args:      []string{"issue", "-domain", "test.example.com", "-hosted-domain", "example.com", "-method", "HTTP"},
+			expect:    exitUsage,
+			expectErr: "invalid validation method: HTTP",
+		},
+		{
+			name:      "error: invalid ARN",
+			args:      []string{"get", "not-an-arn"},
+			expect:    exitUsage,
+			expectErr: "arn: invalid prefix",
+		},
+		{
+			name:      "error: no passphrase",
+			args:      []string{"export", "arn:aws:acm:ap-northeast-1:000000000000:certificate/private"},
+			expect:    exitUsage,
+			expectErr: "GOACM_PASSPHRASE",
+		},
+		{
+			name:      "error: bundle without file",
+			args:      []string{"export", "-format", "pkcs12", "arn:aws:acm:ap-northeast-1:000000000000:certificate/private"},
+			env:       map[string]string{"GOACM_PASSPHRASE": "passphrase"},
+			expect:    exitUsage,
+			expectErr: "-format and -out are required",
+		},
+		{
+			name:      "error: invalid bundle file mode",
+			args:      []string{"export", "-format", "pkcs12", "-out", "bundle.p12", "-mode", "rw", "arn:aws:acm:ap-northeast-1:000000000000:certificate/private"},
+			env:       map[string]string{"GOACM_PASSPHRASE": "passphrase"},
+			expect:    exitUsage,
+			expectErr: "invalid file mode: rw",
+		},
+		{
+			name:      "error: invalid output format",
+			args:      []string{"list", "-output", "xml"},
+			expect:    exitUsage,
+			expectErr: "unknown format: xml",
+		},
+		{
+			name:      "error: non-positive expiry window",
+			args:      []string{"expiry", "-within", "0s"},
+			expect:    exitUsage,
+			expectErr: "-within must be positive",
+		},
+		{
+			name:      "error: non-positive cache TTL",
+			args:      []string{"exporter", "-cache-ttl", "0s"},
+			expect:    exitUsage,
+			expectErr: "-cache-ttl must be positive",
+		},
+		{
+			name:      "error: notify without webhook",
+			args:      []string{"notify", "-state", "state.json"},
+			expect:    exitUsage,
+			expectErr: "-state and at least one webhook are required",
+		},
+		{
+			name:      "error: invalid threshold",
+			args:      []string{"notify", "-state", "state.json", "-slack-webhook", "https://hooks.slack.com/services/xxx", "-thresholds", "30,soon"},
+			expect:    exitUsage,
+			expectErr: "invalid threshold: soon",
+		},
+		{
+			name:      "error: unsupported key algorithm",
+			args:      []string{"issue", "-domain", "example.com", "-hosted-domain", "example.com", "-key-algorithm", "RSA_4096"},
+			expect:    exitUsage,
+			expectErr: "key algorithm is not supported",
+		},
+		{
+			name:      "error: update without preference",
+			args:      []string{"update", "arn:aws:acm:ap-northeast-1:000000000000:certificate/internal"},
+			expect:    exitUsage,
+			expectErr: "-transparency-logging must be ENABLED or DISABLED",
+		},
+		{
+			name:      "error: invalid transparency logging preference",
+			args:      []string{"issue", "-domain", "example.com", "-hosted-domain", "example.com", "-transparency-logging", "enabled"},
+			expect:    exitUsage,
+			expectErr: "invalid transparency logging preference: enabled (supported: ENABLED, DISABLED)",
+		},
+		{
+			name:      "error: update with invalid preference",
+			args:      []string{"update", "-transparency-logging", "ON", "arn:aws:acm:ap-northeast-1:000000000000:certificate/internal"},
+			expect:    exitUsage,
+			expectErr: "invalid transparency logging preference: ON",
+		},
+		{
+			name:      "error: renew without ARN",
+			args:      []string{"renew"},
+			expect:    exitUsage,
+			expectErr: "certificate ARN is required",
+		},
+		{
+			name:      "error: resend email without ARN",
+			args:      []string{"resend-email"},
+			expect:    exitUsage,
+			expectErr: "certificate ARN is required",
+		},
+		{
+			name:      "error: readiness with invalid output format",
+			args:      []string{"readiness", "-repair", "-output", "xml"},
+			expect:    exitUsage,
+			expectErr: "unknown format: xml",
+		},
+		{
+			name:      "error: bulk-delete without filter",
+			args:      []string{"bulk-delete", "-yes"},
+			expect:    exitUsage,
+			expectErr: "at least one of -status",
+		},
+		{
+			name:      "error: bulk-delete with invalid domain pattern",
+			args:      []string{"bulk-delete", "-domain", "["},
+			expect:    exitUsage,
+			expectErr: "invalid domain pattern",
+		},
+		{
+			name:      "error: janitor with negative interval",
+			args:      []string{"janitor", "-interval", "-1m"},
+			expect:    exitUsage,
+			expectErr: "-interval must not be negative",
+		},
+		{
+			name:      "error: apply without manifest",
+			args:      []string{"apply", "-dry-run"},
+			expect:    exitUsage,
+			expectErr: "-f is required",
+		},
+		{
+			name:      "error: invalid tag",
+			args:      []string{"tags", "-add", "env", "arn:aws:acm:ap-northeast-1:000000000000:certificate/private"},
+			expect:    exitUsage,
+			expectErr: "invalid tag",
+		},
+		{
+			name:      "error: tags with unsupported output",
+			args:      []string{"tags", "-output", "xml", "arn:aws:acm:ap-northeast-1:000000000000:certificate/private"},
+			expect:    exitUsage,
+			expectErr: "xml",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			a := &app{
+				stdout: stdout,
+				stderr: stderr,
+				getenv: func(k string) string { return c.env[k] },
+			}
+
+			code := a.run(context.TODO(), c.args)
+			assert.Equal(tt, c.expect, code)
+			assert.Contains(tt, stderr.String(), c.expectErr)
+		})
+	}
+}
+
+func Test_exitCode(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		expect int
+	}{
+		{
+			name:   "nil",
+			err:    nil,
+			expect: exitOK,
+		},
+		{
+			name:   "unknown",
+			err:    errors.New("error"),
+			expect: exitError,
+		},
+		{
+			name:   "usage",
+			err:    usageError{msg: "usage"},
+			expect: exitUsage,
+		},
+		{
+			name:   "not found in ACM",
+			err:    &goacm.ServiceError{Service: goacm.ServiceACM, Err: fmt.Errorf("operation error: %w", &acmTypes.ResourceNotFoundException{})},
+			expect: exitNotFound,
+		},
+		{
+			name:   "in use",
+			err:    &acmTypes.ResourceInUseException{},
+			expect: exitInUse,
+		},
+		{
+			name:   "certificate in use",
+			err:    &goacm.CertificateInUseError{Arn: "arn", InUseBy: []string{"loadbalancer"}},
+			expect: exitInUse,
+		},
+		{
+			name:   "invalid ARN",
+			err:    &acmTypes.InvalidArnException{},
+			expect: exitUsage,
+		},
+		{
+			name:   "certificate status",
+			err:    fmt.Errorf("us-east-1: %w", &goacm.CertificateStatusError{Status: string(acmTypes.CertificateStatusFailed)}),
+			expect: exitCertificateStatus,
+		},
+		{
+			name:   "Route 53",
+			err:    &goacm.ServiceError{Service: goacm.ServiceRoute53, Err: errors.New("access denied")},
+			expect: exitRoute53,
+		},
+		{
+			name:   "partial",
+			err:    &goacm.RegionsError{Errors: map[string]error{"af-south-1": errors.New("error")}},
+			expect: exitPartial,
+		},
+		{
+			name:   "partial certificates",
+			err:    &goacm.CertificatesError{Errors: map[string]error{"arn:aws:acm:ap-northeast-1:000000000000:certificate/xxx": errors.New("error")}},
+			expect: exitPartial,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, exitCode(c.err))
+		})
+	}
+}
+
+func Test_parseTags(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       string
+		wantErr bool
+		expect  map[string]string
+	}{
+		{
+			name:   "normal",
+			s:      "env=prod, team=platform,empty=",
+			expect: map[string]string{"env": "prod", "team": "platform", "empty": ""},
+		},
+		{
+			name:   "normal: empty",
+			s:      "",
+			expect: map[string]string{},
+		},
+		{
+			name:    "error: without value",
+			s:       "env",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tags, err := parseTags(c.s)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, tags)
+		})
+	}
+}
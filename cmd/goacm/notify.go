@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/notify"
+)
+
+func init() {
+	register(command{
+		name:    "notify",
+		summary: "Send alerts of expiring or failed certificates and lost validation records to webhooks.",
+		usage:   "-state file (-webhook url | -slack-webhook url | -teams-webhook url)... [-thresholds d1,d2] [-regions r1,r2|all] [-check-records] [-webhook-timeout duration]",
+		run:     runNotify,
+	})
+}
+
+// webhookFlag is a flag that can be repeated to add webhooks of the format that post with the client.
+func webhookFlag(fs *flag.FlagSet, hooks *[]notify.Notifier, client *http.Client, name string, format notify.Format, usage string) {
+	fs.Func(name, usage, func(url string) error {
+		*hooks = append(*hooks, notify.Webhook{URL: url, Format: format, Client: client})
+		return nil
+	})
+}
+
+func runNotify(ctx context.Context, a *app, args []string) error {
+	var (
+		cf           clientFlags
+		hooks        []notify.Notifier
+		statePath    string
+		thresholds   string
+		regions      string
+		checkRecords bool
+		client       = &http.Client{}
+	)
+	fs := a.flagSet(commands["notify"])
+	cf.register(fs)
+	webhookFlag(fs, &hooks, client, "webhook", notify.FormatGeneric, "URL of a webhook that receives alerts as JSON. Can be repeated.")
+	webhookFlag(fs, &hooks, client, "slack-webhook", notify.FormatSlack, "URL of a Slack incoming webhook. Can be repeated.")
+	webhookFlag(fs, &hooks, client, "teams-webhook", notify.FormatTeams, "URL of a Microsoft Teams incoming webhook. Can be repeated.")
+	fs.StringVar(&statePath, "state", "", "File that records the alerts that have been sent.")
+	fs.StringVar(&thresholds, "thresholds", "30,14,7,1", "Comma separated expiry thresholds in days.")
+	fs.StringVar(&regions, "regions", "", `Comma separated regions to check, or "all". Defaults to the region flag.`)
+	fs.BoolVar(&checkRecords, "check-records", false, "Alert validation records that are missing or have other values.")
+	fs.DurationVar(&client.Timeout, "webhook-timeout", notify.DefaultWebhookTimeout, "Timeout of posting an alert to a webhook.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if statePath == "" || len(hooks) == 0 {
+		return usageError{msg: "-state and at least one webhook are required"}
+	}
+	if client.Timeout <= 0 {
+		return usageError{msg: "-webhook-timeout must be positive"}
+	}
+	days, err := parseThresholds(thresholds)
+	if err != nil {
+		return err
+	}
+
+	var clients []*goacm.GoACM
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+		for _, r := range m.Regions {
+			clients = append(clients, m.Clients[r])
+		}
+	} else {
+		g, err := cf.newGoACM(ctx)
+		if err != nil {
+			return err
+		}
+		clients = append(clients, g)
+	}
+
+	state, err := notify.LoadState(statePath)
+	if err != nil {
+		return err
+	}
+
+	apis := goacm.RegionalACMAPI{}
+	for _, g := range clients {
+		apis[g.Region] = g.ACMAPI()
+	}
+	// alerts of the listed certificates are sent even if some regions or certificates failed
+	cList, lerr := goacm.ListCertificatesInRegions(ctx, apis)
+	var re *goacm.RegionsError
+	if lerr != nil && !errors.As(lerr, &re) {
+		return lerr
+	}
+
+	var readiness []goacm.RenewalReadiness
+	if checkRecords {
+		for _, g := range clients {
+			report, err := g.CheckRenewalReadiness(ctx)
+			if err != nil {
+				return err
+			}
+			readiness = append(readiness, report...)
+		}
+	}
+
+	now := time.Now()
+	alerts := notify.DetectAlerts(cList, readiness, func(o *notify.AlertOptions) {
+		o.Thresholds = days
+		o.Now = now
+	})
+	sent, nerr := notify.Notify(ctx, alerts, hooks, state, now)
+	for _, al := range sent {
+		fmt.Fprintf(a.stdout, "%s\t%s\t%s\n", al.Kind, al.DomainName, al.CertificateArn)
+	}
+	// records of certificates that were not listed are kept
+	state.Resolve(alerts, cList, checkRecords)
+
+	// record the alerts that were sent even if some webhooks failed
+	if err := state.Save(statePath); err != nil {
+		return err
+	}
+	if nerr != nil {
+		return nerr
+	}
+	return lerr
+}
+
+// Returns the thresholds in days of a comma separated string.
+func parseThresholds(s string) ([]int, error) {
+	var days []int
+	for _, v := range splitList(s) {
+		d, err := strconv.Atoi(v)
+		if err != nil || d <= 0 {
+			return nil, usageError{msg: fmt.Sprintf("invalid threshold: %s", v)}
+		}
+		days = append(days, d)
+	}
+	if len(days) == 0 {
+		return nil, usageError{msg: "-thresholds is required"}
+	}
+	return days, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"strconv"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+// outputFlags is a structure that represents flags of the output format.
+type outputFlags struct {
+	output     string
+	columns    string
+	sortBy     string
+	descending bool
+
+	// format and maxWidth are set by parse.
+	format   render.Format
+	maxWidth int
+}
+
+func (f *outputFlags) register(fs *flag.FlagSet, defaultFormat render.Format) {
+	fs.StringVar(&f.output, "output", string(defaultFormat), "Output format, table, json, yaml or csv.")
+	fs.StringVar(&f.columns, "columns", "", `Comma separated columns of table and csv such as "domainName,validationRecordSet.name".`)
+	fs.StringVar(&f.sortBy, "sort", "", "Column to sort by.")
+	fs.BoolVar(&f.descending, "desc", false, "Sort in descending order.")
+}
+
+// Parses the output format. It is called after parsing flags so that invalid formats fail before calling APIs.
+// The width of tables is limited by the COLUMNS environment variable.
+func (f *outputFlags) parse(a *app) error {
+	format, err := render.ParseFormat(f.output)
+	if err != nil {
+		return usageError{msg: err.Error()}
+	}
+	f.format = format
+	f.maxWidth, _ = strconv.Atoi(a.getenv("COLUMNS"))
+	return nil
+}
+
+func (f *outputFlags) options(o *render.Options) {
+	o.Columns = splitList(f.columns)
+	o.SortBy = f.sortBy
+	o.Descending = f.descending
+	o.MaxWidth = f.maxWidth
+}
+
+// Writes the certificates.
+func (f *outputFlags) certificates(a *app, cList []goacm.Certificate) error {
+	return renderError(render.Certificates(a.stdout, f.format, cList, f.options))
+}
+
+// Writes the certificate.
+func (f *outputFlags) certificate(a *app, c goacm.Certificate) error {
+	return renderError(render.Certificate(a.stdout, f.format, c, f.options))
+}
+
+// Writes the items with the default columns of table and csv.
+func (f *outputFlags) items(a *app, items interface{}, defaultColumns []string) error {
+	return renderError(render.Items(a.stdout, f.format, items, defaultColumns, f.options))
+}
+
+// Writes the result of issuing a certificate.
+func (f *outputFlags) issueCertificateResult(a *app, r goacm.IssueCertificateResult) error {
+	return renderError(render.IssueCertificateResult(a.stdout, f.format, r, f.options))
+}
+
+// Writes the plan. Tables are written in a diff-like format.
+func (f *outputFlags) plan(a *app, plan goacm.Plan) error {
+	return renderError(render.Plan(a.stdout, f.format, plan))
+}
+
+// Writes the result of reconciling certificates. Tables are written in a diff-like format.
+func (f *outputFlags) reconcileResult(a *app, r goacm.ReconcileResult) error {
+	return renderError(render.ReconcileResult(a.stdout, f.format, r))
+}
+
+// regionalColumns are default columns of certificates replicated into regions.
+var regionalColumns = []string{"region", "status", "certificateArn"}
+
+// Unknown columns are usage errors.
+func renderError(err error) error {
+	var ce *render.ColumnError
+	if errors.As(err, &ce) {
+		return usageError{msg: err.Error()}
+	}
+	return err
+}
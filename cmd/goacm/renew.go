@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "renew",
+		summary: "Renew a private certificate.",
+		usage:   "<certificate-arn>",
+		run:     runRenew,
+	})
+	register(command{
+		name:    "renewal",
+		summary: "Show the status of the managed renewal of a certificate.",
+		usage:   "[-output format] <certificate-arn>",
+		run:     runRenewal,
+	})
+	register(command{
+		name:    "readiness",
+		summary: "Check the DNS validation records that the managed renewal needs.",
+		usage:   "[-repair] [-output format] [certificate-arn ...]",
+		run:     runReadiness,
+	})
+}
+
+// renewalColumns are default columns of renewal summaries.
+var renewalColumns = []string{"eligibility", "status", "reason", "updatedAt"}
+
+// readinessColumns are default columns of renewal readiness reports.
+var readinessColumns = []string{"certificateArn", "domainName", "ready", "error"}
+
+func runRenew(ctx context.Context, a *app, args []string) error {
+	var cf clientFlags
+	fs := a.flagSet(commands["renew"])
+	cf.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	if err := g.RenewCertificate(ctx, certificateArn); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "renewing\t%s\n", certificateArn)
+	return nil
+}
+
+func runRenewal(ctx context.Context, a *app, args []string) error {
+	var (
+		cf clientFlags
+		of outputFlags
+	)
+	fs := a.flagSet(commands["renewal"])
+	cf.register(fs)
+	of.register(fs, render.FormatYAML)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	s, err := g.RenewalStatus(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	return renderError(render.Item(a.stdout, of.format, s, renewalColumns, of.options))
+}
+
+func runReadiness(ctx context.Context, a *app, args []string) error {
+	var (
+		cf     clientFlags
+		of     outputFlags
+		repair bool
+	)
+	fs := a.flagSet(commands["readiness"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.BoolVar(&repair, "repair", false, "Create missing validation records and update records with other values.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACM(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := g.CheckRenewalReadiness(ctx, func(o *goacm.RenewalReadinessOptions) {
+		if fs.NArg() > 0 {
+			o.CertificateArns = fs.Args()
+		}
+		o.Repair = repair
+	})
+	if report != nil {
+		if rerr := of.items(a, report, readinessColumns); rerr != nil {
+			return rerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, r := range report {
+		if !r.Ready {
+			return errors.New("some certificates are not ready for renewal")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+func init() {
+	register(command{
+		name:    "tags",
+		summary: "List, add or remove tags of a certificate.",
+		usage:   "[-add k1=v1,k2=v2] [-remove k1,k2] [-output format] <certificate-arn>",
+		run:     runTags,
+	})
+}
+
+// tag is a tag of a certificate in the output.
+type tag struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// tagColumns are default columns of tags.
+var tagColumns = []string{"key", "value"}
+
+func runTags(ctx context.Context, a *app, args []string) error {
+	var (
+		cf     clientFlags
+		add    string
+		remove string
+		of     outputFlags
+	)
+	fs := a.flagSet(commands["tags"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.StringVar(&add, "add", "", "Comma separated key=value pairs of tags to add.")
+	fs.StringVar(&remove, "remove", "", "Comma separated keys of tags to remove.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	addTags, err := parseTags(add)
+	if err != nil {
+		return err
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	if err := goacm.AddCertificateTags(ctx, g.ACMAPI(), certificateArn, addTags); err != nil {
+		return err
+	}
+	if err := goacm.RemoveCertificateTags(ctx, g.ACMAPI(), certificateArn, splitList(remove)); err != nil {
+		return err
+	}
+
+	tags, err := goacm.ListCertificateTags(ctx, g.ACMAPI(), certificateArn)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]tag, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, tag{Key: k, Value: tags[k]})
+	}
+	return of.items(a, items, tagColumns)
+}
+
+// Returns tags of comma separated key=value pairs.
+func parseTags(s string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, kv := range splitList(s) {
+		i := strings.Index(kv, "=")
+		if i <= 0 {
+			return nil, usageError{msg: fmt.Sprintf("invalid tag, expected key=value: %s", kv)}
+		}
+		tags[kv[:i]] = kv[i+1:]
+	}
+	return tags, nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+)
+
+// defaultPassphraseEnv is a name of the environment variable that holds the passphrase of exported private keys.
+const defaultPassphraseEnv = "GOACM_PASSPHRASE"
+
+func init() {
+	register(command{
+		name:    "import",
+		summary: "Import a certificate.",
+		usage:   "-cert file -key file [-chain file] [-regions r1,r2] [-output format]",
+		run:     runImport,
+	})
+	register(command{
+		name:    "export",
+		summary: "Export a private certificate with the encrypted private key.",
+		usage:   "[-passphrase-env name] (-out-dir dir | -format pem|pkcs12|jks -out file [-mode perm] [-alias name] [-password-env name] [-legacy-pkcs12]) <certificate-arn>",
+		run:     runExport,
+	})
+}
+
+func runImport(ctx context.Context, a *app, args []string) error {
+	var (
+		cf        clientFlags
+		of        outputFlags
+		certFile  string
+		keyFile   string
+		chainFile string
+		regions   string
+	)
+	fs := a.flagSet(commands["import"])
+	cf.register(fs)
+	of.register(fs, render.FormatTable)
+	fs.StringVar(&certFile, "cert", "", "PEM file of the certificate.")
+	fs.StringVar(&keyFile, "key", "", "PEM file of the unencrypted private key.")
+	fs.StringVar(&chainFile, "chain", "", "PEM file of the certificate chain.")
+	fs.StringVar(&regions, "regions", "", "Comma separated regions to import the certificate into.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := of.parse(a); err != nil {
+		return err
+	}
+
+	if certFile == "" || keyFile == "" {
+		return usageError{msg: "-cert and -key are required"}
+	}
+
+	cert, err := os.ReadFile(certFile)
+	if err != nil {
+		return err
+	}
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	var chain []byte
+	if chainFile != "" {
+		if chain, err = os.ReadFile(chainFile); err != nil {
+			return err
+		}
+	}
+
+	if regions != "" {
+		m, err := cf.newMultiRegionGoACM(ctx, splitList(regions))
+		if err != nil {
+			return err
+		}
+
+		res, err := m.ReplicateImportedCertificate(ctx, cert, key, chain)
+		if err != nil {
+			return err
+		}
+
+		return of.items(a, res.Regions, regionalColumns)
+	}
+
+	g, err := cf.newGoACM(ctx)
+	if err != nil {
+		return err
+	}
+
+	certificateArn, err := goacm.ImportCertificate(ctx, g.ACMAPI(), cert, key, chain)
+	if err != nil {
+		return err
+	}
+
+	return of.items(a, []goacm.RegionalCertificateResult{{Region: g.Region, CertificateArn: certificateArn}}, regionalColumns)
+}
+
+func runExport(ctx context.Context, a *app, args []string) error {
+	var (
+		cf            clientFlags
+		passphraseEnv string
+		outDir        string
+		format        string
+		out           string
+		mode          string
+		alias         string
+		passwordEnv   string
+		legacyPKCS12  bool
+	)
+	fs := a.flagSet(commands["export"])
+	cf.register(fs)
+	fs.StringVar(&passphraseEnv, "passphrase-env", defaultPassphraseEnv, "Name of the environment variable that holds the passphrase of the private key.")
+	fs.StringVar(&outDir, "out-dir", "", "Directory to write certificate.pem, chain.pem and private_key.pem. Defaults to stdout.")
+	fs.StringVar(&format, "format", "", "Format of a bundle with the decrypted private key, pem, pkcs12 or jks.")
+	fs.StringVar(&out, "out", "", "File to write the bundle.")
+	fs.StringVar(&mode, "mode", "0600", "Permission of the bundle file in octal.")
+	fs.StringVar(&alias, "alias", goacm.DefaultBundleAlias, "Alias of the entry in JKS bundles.")
+	fs.StringVar(&passwordEnv, "password-env", "", "Name of the environment variable that holds the password of PKCS#12 and JKS bundles. Defaults to the passphrase.")
+	fs.BoolVar(&legacyPKCS12, "legacy-pkcs12", false, "Encrypt PKCS#12 bundles with the legacy 3DES for old clients instead of AES-256.")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	certificateArn, err := arnArg(fs)
+	if err != nil {
+		return err
+	}
+
+	passphrase := a.getenv(passphraseEnv)
+	if passphrase == "" {
+		return usageError{msg: fmt.Sprintf("passphrase is required in the environment variable %s", passphraseEnv)}
+	}
+
+	if format != "" || out != "" {
+		if format == "" || out == "" || outDir != "" {
+			return usageError{msg: "-format and -out are required for bundles, and cannot be used with -out-dir"}
+		}
+
+		supported := false
+		for _, f := range goacm.BundleFormats {
+			supported = supported || format == string(f)
+		}
+		if !supported {
+			return usageError{msg: fmt.Sprintf("unsupported bundle format: %s", format)}
+		}
+
+		perm, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || perm > 0777 {
+			return usageError{msg: fmt.Sprintf("invalid file mode: %s", mode)}
+		}
+
+		var password []byte
+		if passwordEnv != "" {
+			if password = []byte(a.getenv(passwordEnv)); len(password) == 0 {
+				return usageError{msg: fmt.Sprintf("password is required in the environment variable %s", passwordEnv)}
+			}
+		}
+
+		g, err := cf.newGoACMForArn(ctx, certificateArn)
+		if err != nil {
+			return err
+		}
+
+		if err := goacm.ExportCertificateBundle(ctx, g.ACMAPI(), certificateArn, []byte(passphrase), out, func(o *goacm.BundleOptions) {
+			o.Format = goacm.BundleFormat(format)
+			o.FileMode = os.FileMode(perm)
+			o.Alias = alias
+			o.Password = password
+			o.LegacyPKCS12 = legacyPKCS12
+		}); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.stdout, "wrote\t%s\n", out)
+		return nil
+	}
+
+	g, err := cf.newGoACMForArn(ctx, certificateArn)
+	if err != nil {
+		return err
+	}
+
+	e, err := goacm.ExportCertificate(ctx, g.ACMAPI(), certificateArn, []byte(passphrase))
+	if err != nil {
+		return err
+	}
+
+	if outDir == "" {
+		fmt.Fprint(a.stdout, e.Certificate, e.CertificateChain, e.PrivateKey)
+		return nil
+	}
+
+	files := []struct {
+		name string
+		data string
+		perm os.FileMode
+	}{
+		{name: "certificate.pem", data: e.Certificate, perm: 0644},
+		{name: "chain.pem", data: e.CertificateChain, perm: 0644},
+		{name: "private_key.pem", data: e.PrivateKey, perm: 0600},
+	}
+	for _, f := range files {
+		p := filepath.Join(outDir, f.name)
+		if err := os.WriteFile(p, []byte(f.data), f.perm); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "wrote\t%s\n", p)
+	}
+
+	return nil
+}
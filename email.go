@@ -0,0 +1,85 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// ValidationEmail is a structure that represents a validation email sent for a domain of a certificate.
+type ValidationEmail struct {
+	DomainName       string `json:"domainName" yaml:"domainName"`
+	ValidationDomain string `json:"validationDomain" yaml:"validationDomain"`
+
+	// ValidationEmails are the approver addresses that received the email.
+	ValidationEmails []string `json:"validationEmails" yaml:"validationEmails"`
+
+	// Error is a message of the error if the email was not sent.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ResendValidationEmail resends the validation emails of a certificate validated by email that is pending validation.
+// An email is sent for each pair of a domain and its validation domain that is not validated yet.
+// It returns the emails, and an error that joins the errors of the domains.
+func ResendValidationEmail(ctx context.Context, api ACMDescribeResendValidationEmailAPI, arn string) ([]ValidationEmail, error) {
+	out, err := api.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(arn)})
+	if err != nil {
+		return nil, err
+	}
+
+	d := out.Certificate
+	if d.Status != types.CertificateStatusPendingValidation {
+		return nil, fmt.Errorf("certificate status is %s, not %s: %s", d.Status, types.CertificateStatusPendingValidation, arn)
+	}
+
+	emails := []ValidationEmail{}
+	msgs := []string{}
+	for _, dv := range d.DomainValidationOptions {
+		if dv.ValidationMethod != types.ValidationMethodEmail {
+			continue
+		}
+		if dv.ValidationStatus == types.DomainStatusSuccess {
+			continue
+		}
+
+		e := ValidationEmail{
+			DomainName:       aws.ToString(dv.DomainName),
+			ValidationDomain: aws.ToString(dv.ValidationDomain),
+			ValidationEmails: dv.ValidationEmails,
+		}
+		// the validation domain is the domain itself unless another one was requested
+		if e.ValidationDomain == "" {
+			e.ValidationDomain = e.DomainName
+		}
+
+		in := acm.ResendValidationEmailInput{
+			CertificateArn:   aws.String(arn),
+			Domain:           aws.String(e.DomainName),
+			ValidationDomain: aws.String(e.ValidationDomain),
+		}
+		if _, err := api.ResendValidationEmail(ctx, &in); err != nil {
+			e.Error = err.Error()
+			msgs = append(msgs, fmt.Sprintf("%s: %v", e.DomainName, err))
+		}
+		emails = append(emails, e)
+	}
+
+	if len(emails) == 0 {
+		return nil, fmt.Errorf("certificate has no domains pending validation by email: %s", arn)
+	}
+	if len(msgs) > 0 {
+		return emails, errors.New(strings.Join(msgs, "; "))
+	}
+
+	return emails, nil
+}
+
+// ResendValidationEmail resends the validation emails of the certificate with the ACM client.
+func (g *GoACM) ResendValidationEmail(ctx context.Context, arn string) ([]ValidationEmail, error) {
+	return ResendValidationEmail(ctx, g.ACMAPI(), arn)
+}
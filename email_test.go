@@ -0,0 +1,110 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ResendValidationEmail(t *testing.T) {
+	emails := []string{"admin@example.com", "webmaster@example.com"}
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:                     "arn:aws:acm:ap-northeast-1:000000000000:certificate/pending",
+				DomainName:              "example.com",
+				SubjectAlternativeNames: []string{"www.example.com"},
+				Status:                  string(types.CertificateStatusPendingValidation),
+				ValidationMethod:        string(types.ValidationMethodEmail),
+				ValidationEmails:        emails,
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:              "arn:aws:acm:ap-northeast-1:000000000000:certificate/issued",
+				DomainName:       "example.com",
+				Status:           string(types.CertificateStatusIssued),
+				ValidationMethod: string(types.ValidationMethodEmail),
+				ValidationEmails: emails,
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:              "arn:aws:acm:ap-northeast-1:000000000000:certificate/dns",
+				DomainName:       "example.com",
+				Status:           string(types.CertificateStatusPendingValidation),
+				ValidationMethod: string(types.ValidationMethodDns),
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		arn      string
+		failWith string
+		wantErr  bool
+		expect   []goacm.ValidationEmail
+	}{
+		{
+			name: "normal",
+			arn:  ap[0].Certificate.Arn,
+			expect: []goacm.ValidationEmail{
+				{DomainName: "example.com", ValidationDomain: "example.com", ValidationEmails: emails},
+				{DomainName: "www.example.com", ValidationDomain: "www.example.com", ValidationEmails: emails},
+			},
+		},
+		{
+			name:     "error: failed for a domain",
+			arn:      ap[0].Certificate.Arn,
+			failWith: "www.example.com",
+			wantErr:  true,
+			expect: []goacm.ValidationEmail{
+				{DomainName: "example.com", ValidationDomain: "example.com", ValidationEmails: emails},
+				{DomainName: "www.example.com", ValidationDomain: "www.example.com", ValidationEmails: emails, Error: "failed"},
+			},
+		},
+		{
+			name:    "error: not pending validation",
+			arn:     ap[1].Certificate.Arn,
+			wantErr: true,
+		},
+		{
+			name:    "error: not validated by email",
+			arn:     ap[2].Certificate.Arn,
+			wantErr: true,
+		},
+		{
+			name:    "error: not found",
+			arn:     "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			aAPI := goacm.NewMockACMAPI(ap)
+			resend := aAPI.ResendValidationEmailAPI
+			aAPI.ResendValidationEmailAPI = func(ctx context.Context, params *acm.ResendValidationEmailInput, optFns ...func(*acm.Options)) (*acm.ResendValidationEmailOutput, error) {
+				if aws.ToString(params.Domain) == c.failWith {
+					return nil, errors.New("failed")
+				}
+				return resend(ctx, params, optFns...)
+			}
+
+			sent, err := goacm.ResendValidationEmail(context.TODO(), aAPI, c.arn)
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.Equal(tt, c.expect, sent)
+		})
+	}
+}
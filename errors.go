@@ -0,0 +1,93 @@
+package goacm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ServiceACM is a name of ACM used in errors.
+	ServiceACM = "ACM"
+
+	// ServiceRoute53 is a name of Route 53 used in errors.
+	ServiceRoute53 = "Route 53"
+)
+
+// ServiceError is an error that occurred in calling the API of ACM or Route 53.
+// AccountID is the ID of the AWS account the client belongs to, and is empty if unknown.
+type ServiceError struct {
+	Service   string
+	AccountID string
+	Err       error
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("%s: %v", serviceLabel(e.Service, e.AccountID), e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// Returns a label such as "Route 53 (account 000000000000)".
+func serviceLabel(service, accountID string) string {
+	if accountID == "" {
+		return service
+	}
+	return fmt.Sprintf("%s (account %s)", service, accountID)
+}
+
+// CertificateInUseError is an error that represents the certificate cannot be deleted
+// because AWS resources such as load balancers or CloudFront distributions use it.
+type CertificateInUseError struct {
+	Arn     string
+	InUseBy []string
+}
+
+func (e *CertificateInUseError) Error() string {
+	return fmt.Sprintf("certificate is in use by %s: %s", strings.Join(e.InUseBy, ", "), e.Arn)
+}
+
+// Returns *CertificateInUseError if the certificate is in use, otherwise nil.
+func inUseError(arn string, inUseBy []string) error {
+	if len(inUseBy) == 0 {
+		return nil
+	}
+	return &CertificateInUseError{Arn: arn, InUseBy: inUseBy}
+}
+
+// CertificateStatusError is an error that represents the certificate reached a status
+// that will never become ISSUED, such as FAILED or VALIDATION_TIMED_OUT.
+type CertificateStatusError struct {
+	Arn           string
+	Status        string
+	FailureReason string
+}
+
+func (e *CertificateStatusError) Error() string {
+	if e.FailureReason == "" {
+		return fmt.Sprintf("certificate status is %s: %s", e.Status, e.Arn)
+	}
+	return fmt.Sprintf("certificate status is %s (%s): %s", e.Status, e.FailureReason, e.Arn)
+}
+
+// CertificatesError is an error that represents some certificates could not be described in listing them.
+// Errors is a map of the ARN and the error of the certificate.
+type CertificatesError struct {
+	Errors map[string]error
+}
+
+func (e *CertificatesError) Error() string {
+	arns := make([]string, 0, len(e.Errors))
+	for arn := range e.Errors {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+
+	msgs := make([]string, 0, len(arns))
+	for _, arn := range arns {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", arn, e.Errors[arn]))
+	}
+	return strings.Join(msgs, "; ")
+}
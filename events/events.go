@@ -0,0 +1,250 @@
+// Package events parses the events that ACM emits to Amazon EventBridge, and dispatches them to callbacks
+// with the certificates of the events, to build AWS Lambda functions on goacm.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/michimani/goacm"
+)
+
+// Source is the source of the events of ACM.
+const Source = "aws.acm"
+
+// Detail types of the events of ACM.
+const (
+	DetailTypeApproachingExpiration = "ACM Certificate Approaching Expiration"
+	DetailTypeRenewalActionRequired = "ACM Certificate Renewal Action Required"
+	DetailTypeExpired               = "ACM Certificate Expired"
+	DetailTypeAvailable             = "ACM Certificate Available"
+)
+
+// Event is a structure that represents an EventBridge event of ACM.
+// Detail is decoded by the methods of the detail types.
+type Event struct {
+	Version    string          `json:"version"`
+	ID         string          `json:"id"`
+	DetailType string          `json:"detail-type"`
+	Source     string          `json:"source"`
+	Account    string          `json:"account"`
+	Time       time.Time       `json:"time"`
+	Region     string          `json:"region"`
+	Resources  []string        `json:"resources"`
+	Detail     json.RawMessage `json:"detail"`
+}
+
+// ApproachingExpirationDetail is a structure that represents the detail of "ACM Certificate Approaching Expiration".
+// ACM emits it daily from 45 days before a certificate expires.
+type ApproachingExpirationDetail struct {
+	DaysToExpiry int    `json:"DaysToExpiry"`
+	CommonName   string `json:"CommonName"`
+}
+
+// RenewalActionRequiredDetail is a structure that represents the detail of "ACM Certificate Renewal Action Required".
+// ACM emits it when the managed renewal needs an action, such as validating the domains.
+type RenewalActionRequiredDetail struct {
+	CertificateType           string     `json:"CertificateType"`
+	CommonName                string     `json:"CommonName"`
+	DomainValidationMethod    string     `json:"DomainValidationMethod"`
+	CertificateCreatedDate    *time.Time `json:"CertificateCreatedDate,omitempty"`
+	CertificateExpirationDate *time.Time `json:"CertificateExpirationDate,omitempty"`
+	DaysToExpiry              int        `json:"DaysToExpiry"`
+	InUse                     bool       `json:"InUse"`
+	Exported                  bool       `json:"Exported"`
+	RenewalStatus             string     `json:"RenewalStatus,omitempty"`
+	RenewalStatusReason       string     `json:"RenewalStatusReason,omitempty"`
+}
+
+// ExpiredDetail is a structure that represents the detail of "ACM Certificate Expired".
+type ExpiredDetail struct {
+	CommonName                string     `json:"CommonName"`
+	CertificateExpirationDate *time.Time `json:"CertificateExpirationDate,omitempty"`
+	InUse                     bool       `json:"InUse"`
+}
+
+// AvailableDetail is a structure that represents the detail of "ACM Certificate Available".
+// Action is ISSUANCE, RENEWAL or REIMPORT.
+type AvailableDetail struct {
+	Action                    string     `json:"Action"`
+	CertificateType           string     `json:"CertificateType"`
+	CommonName                string     `json:"CommonName"`
+	DomainValidationMethod    string     `json:"DomainValidationMethod"`
+	CertificateCreatedDate    *time.Time `json:"CertificateCreatedDate,omitempty"`
+	CertificateExpirationDate *time.Time `json:"CertificateExpirationDate,omitempty"`
+	DaysToExpiry              int        `json:"DaysToExpiry"`
+	InUse                     bool       `json:"InUse"`
+	Exported                  bool       `json:"Exported"`
+}
+
+// DetailTypeError is an error that represents the detail type of the event is not the expected one.
+type DetailTypeError struct {
+	DetailType string
+	Expected   string
+}
+
+func (e *DetailTypeError) Error() string {
+	return fmt.Sprintf("detail type is %q, not %q", e.DetailType, e.Expected)
+}
+
+// Parse parses the JSON of an event of ACM.
+func Parse(b []byte) (Event, error) {
+	var e Event
+	if err := json.Unmarshal(b, &e); err != nil {
+		return Event{}, err
+	}
+	if e.Source != Source {
+		return Event{}, fmt.Errorf("source of the event is %q, not %q", e.Source, Source)
+	}
+	return e, nil
+}
+
+// CertificateArn returns the ARN of the certificate of the event, or "" if the event has no resources.
+func (e Event) CertificateArn() string {
+	if len(e.Resources) == 0 {
+		return ""
+	}
+	return e.Resources[0]
+}
+
+// ApproachingExpiration decodes the detail of "ACM Certificate Approaching Expiration".
+func (e Event) ApproachingExpiration() (ApproachingExpirationDetail, error) {
+	var d ApproachingExpirationDetail
+	return d, e.decode(DetailTypeApproachingExpiration, &d)
+}
+
+// RenewalActionRequired decodes the detail of "ACM Certificate Renewal Action Required".
+func (e Event) RenewalActionRequired() (RenewalActionRequiredDetail, error) {
+	var d RenewalActionRequiredDetail
+	return d, e.decode(DetailTypeRenewalActionRequired, &d)
+}
+
+// Expired decodes the detail of "ACM Certificate Expired".
+func (e Event) Expired() (ExpiredDetail, error) {
+	var d ExpiredDetail
+	return d, e.decode(DetailTypeExpired, &d)
+}
+
+// Available decodes the detail of "ACM Certificate Available".
+func (e Event) Available() (AvailableDetail, error) {
+	var d AvailableDetail
+	return d, e.decode(DetailTypeAvailable, &d)
+}
+
+func (e Event) decode(detailType string, v interface{}) error {
+	if e.DetailType != detailType {
+		return &DetailTypeError{DetailType: e.DetailType, Expected: detailType}
+	}
+	if len(e.Detail) == 0 {
+		return nil
+	}
+	return json.Unmarshal(e.Detail, v)
+}
+
+// Handler is a structure that resolves events to the certificates, and dispatches them to the callbacks.
+// Events without callbacks and events of other detail types are ignored.
+//
+// Handle can be passed to lambda.Start of github.com/aws/aws-lambda-go.
+type Handler struct {
+	// API is the ACM client in the region of the events.
+	API goacm.ACMDescribeCertificateAPI
+
+	OnApproachingExpiration func(ctx context.Context, e Event, d ApproachingExpirationDetail, c goacm.Certificate) error
+	OnRenewalActionRequired func(ctx context.Context, e Event, d RenewalActionRequiredDetail, c goacm.Certificate) error
+	OnExpired               func(ctx context.Context, e Event, d ExpiredDetail, c goacm.Certificate) error
+	OnAvailable             func(ctx context.Context, e Event, d AvailableDetail, c goacm.Certificate) error
+}
+
+// Handle resolves the event to the certificate with GetCertificate, and calls the callback of the detail type.
+func (h Handler) Handle(ctx context.Context, e Event) error {
+	if e.Source != Source {
+		return fmt.Errorf("source of the event is %q, not %q", e.Source, Source)
+	}
+
+	switch e.DetailType {
+	case DetailTypeApproachingExpiration:
+		if h.OnApproachingExpiration == nil {
+			return nil
+		}
+		d, err := e.ApproachingExpiration()
+		if err != nil {
+			return err
+		}
+		c, err := h.certificate(ctx, e)
+		if err != nil {
+			return err
+		}
+		return h.OnApproachingExpiration(ctx, e, d, c)
+
+	case DetailTypeRenewalActionRequired:
+		if h.OnRenewalActionRequired == nil {
+			return nil
+		}
+		d, err := e.RenewalActionRequired()
+		if err != nil {
+			return err
+		}
+		c, err := h.certificate(ctx, e)
+		if err != nil {
+			return err
+		}
+		return h.OnRenewalActionRequired(ctx, e, d, c)
+
+	case DetailTypeExpired:
+		if h.OnExpired == nil {
+			return nil
+		}
+		d, err := e.Expired()
+		if err != nil {
+			return err
+		}
+		c, err := h.certificate(ctx, e)
+		if err != nil {
+			return err
+		}
+		return h.OnExpired(ctx, e, d, c)
+
+	case DetailTypeAvailable:
+		if h.OnAvailable == nil {
+			return nil
+		}
+		d, err := e.Available()
+		if err != nil {
+			return err
+		}
+		c, err := h.certificate(ctx, e)
+		if err != nil {
+			return err
+		}
+		return h.OnAvailable(ctx, e, d, c)
+	}
+
+	return nil
+}
+
+// HandleJSON parses the JSON of the event, and handles it.
+func (h Handler) HandleJSON(ctx context.Context, b []byte) error {
+	e, err := Parse(b)
+	if err != nil {
+		return err
+	}
+	return h.Handle(ctx, e)
+}
+
+// Returns the certificate of the event with Region set.
+func (h Handler) certificate(ctx context.Context, e Event) (goacm.Certificate, error) {
+	arn := e.CertificateArn()
+	if arn == "" {
+		return goacm.Certificate{}, errors.New("event has no certificate ARN in resources")
+	}
+
+	c, err := goacm.GetCertificate(ctx, h.API, arn)
+	if err != nil {
+		return goacm.Certificate{}, err
+	}
+	c.Region = e.Region
+	return c, nil
+}
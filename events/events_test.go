@@ -0,0 +1,218 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/events"
+	"github.com/stretchr/testify/assert"
+)
+
+const approachingExpiration = `{
+  "version": "0",
+  "id": "9c95e8e4-96a4-ef3f-b739-b6aa5b193afb",
+  "detail-type": "ACM Certificate Approaching Expiration",
+  "source": "aws.acm",
+  "account": "000000000000",
+  "time": "2022-01-01T00:00:00Z",
+  "region": "ap-northeast-1",
+  "resources": ["arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring"],
+  "detail": {
+    "DaysToExpiry": 31,
+    "CommonName": "example.com"
+  }
+}`
+
+const renewalActionRequired = `{
+  "version": "0",
+  "id": "2f5bc2d6-3d1b-0f8c-9a4a-59e1d0a5e4c0",
+  "detail-type": "ACM Certificate Renewal Action Required",
+  "source": "aws.acm",
+  "account": "000000000000",
+  "time": "2022-01-01T00:00:00Z",
+  "region": "ap-northeast-1",
+  "resources": ["arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring"],
+  "detail": {
+    "CertificateType": "AMAZON_ISSUED",
+    "CommonName": "example.com",
+    "DomainValidationMethod": "EMAIL",
+    "CertificateCreatedDate": "2021-02-01T00:00:00Z",
+    "CertificateExpirationDate": "2022-02-01T00:00:00Z",
+    "DaysToExpiry": 31,
+    "InUse": true,
+    "Exported": false
+  }
+}`
+
+func Test_Parse(t *testing.T) {
+	cases := []struct {
+		name    string
+		json    string
+		wantErr bool
+		expect  events.Event
+	}{
+		{
+			name: "normal",
+			json: approachingExpiration,
+			expect: events.Event{
+				Version:    "0",
+				ID:         "9c95e8e4-96a4-ef3f-b739-b6aa5b193afb",
+				DetailType: events.DetailTypeApproachingExpiration,
+				Source:     events.Source,
+				Account:    "000000000000",
+				Time:       time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+				Region:     "ap-northeast-1",
+				Resources:  []string{"arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring"},
+			},
+		},
+		{
+			name:    "error: other source",
+			json:    `{"source": "aws.ec2", "detail-type": "EC2 Instance State-change Notification"}`,
+			wantErr: true,
+		},
+		{
+			name:    "error: invalid JSON",
+			json:    `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			e, err := events.Parse([]byte(c.json))
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			e.Detail = nil
+			assert.Equal(tt, c.expect, e)
+		})
+	}
+}
+
+func Test_Event_RenewalActionRequired(t *testing.T) {
+	e, err := events.Parse([]byte(renewalActionRequired))
+	assert.NoError(t, err)
+
+	d, err := e.RenewalActionRequired()
+	assert.NoError(t, err)
+	assert.Equal(t, events.RenewalActionRequiredDetail{
+		CertificateType:           string(types.CertificateTypeAmazonIssued),
+		CommonName:                "example.com",
+		DomainValidationMethod:    string(types.ValidationMethodEmail),
+		CertificateCreatedDate:    aws.Time(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+		CertificateExpirationDate: aws.Time(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC)),
+		DaysToExpiry:              31,
+		InUse:                     true,
+	}, d)
+
+	_, err = e.ApproachingExpiration()
+	var dte *events.DetailTypeError
+	assert.True(t, errors.As(err, &dte))
+}
+
+func Test_Handler_HandleJSON(t *testing.T) {
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring",
+				DomainName: "example.com",
+				Status:     string(types.CertificateStatusIssued),
+				Type:       string(types.CertificateTypeAmazonIssued),
+			},
+		},
+	}
+	expectCertificate := ap[0].Certificate
+	expectCertificate.Region = "ap-northeast-1"
+
+	cases := []struct {
+		name           string
+		json           string
+		noCallback     bool
+		callbackErr    error
+		wantErr        bool
+		expectCalled   string
+		expectCalledOn goacm.Certificate
+	}{
+		{
+			name:           "normal: approaching expiration",
+			json:           approachingExpiration,
+			expectCalled:   "approaching expiration 31",
+			expectCalledOn: expectCertificate,
+		},
+		{
+			name:           "normal: renewal action required",
+			json:           renewalActionRequired,
+			expectCalled:   "renewal action required EMAIL",
+			expectCalledOn: expectCertificate,
+		},
+		{
+			name:       "normal: no callback",
+			json:       approachingExpiration,
+			noCallback: true,
+		},
+		{
+			name: "normal: other detail type",
+			json: `{"source": "aws.acm", "detail-type": "ACM Certificate Available", "resources": ["arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring"]}`,
+		},
+		{
+			name:        "error: callback failed",
+			json:        approachingExpiration,
+			callbackErr: errors.New("failed"),
+			wantErr:     true,
+			// the callback is called, and its error is returned
+			expectCalled:   "approaching expiration 31",
+			expectCalledOn: expectCertificate,
+		},
+		{
+			name:    "error: certificate not found",
+			json:    `{"source": "aws.acm", "detail-type": "ACM Certificate Approaching Expiration", "resources": ["arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found"], "detail": {}}`,
+			wantErr: true,
+		},
+		{
+			name:    "error: no resources",
+			json:    `{"source": "aws.acm", "detail-type": "ACM Certificate Approaching Expiration", "detail": {}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			var (
+				called   string
+				calledOn goacm.Certificate
+			)
+			h := events.Handler{API: goacm.NewMockACMAPI(ap)}
+			if !c.noCallback {
+				h.OnApproachingExpiration = func(ctx context.Context, e events.Event, d events.ApproachingExpirationDetail, cert goacm.Certificate) error {
+					called = fmt.Sprintf("approaching expiration %d", d.DaysToExpiry)
+					calledOn = cert
+					return c.callbackErr
+				}
+				h.OnRenewalActionRequired = func(ctx context.Context, e events.Event, d events.RenewalActionRequiredDetail, cert goacm.Certificate) error {
+					called = "renewal action required " + d.DomainValidationMethod
+					calledOn = cert
+					return c.callbackErr
+				}
+			}
+
+			err := h.HandleJSON(context.TODO(), []byte(c.json))
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.Equal(tt, c.expectCalled, called)
+			assert.Equal(tt, c.expectCalledOn, calledOn)
+		})
+	}
+}
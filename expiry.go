@@ -0,0 +1,158 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// DefaultExpiryWindow is a default window in which certificates are expiring soon.
+const DefaultExpiryWindow = 30 * 24 * time.Hour
+
+// ExpiryClass is a type that represents a class of a certificate by its expiry.
+type ExpiryClass string
+
+// Classes of expiry in order of urgency.
+const (
+	// ExpiryClassExpired means the certificate has expired.
+	ExpiryClassExpired ExpiryClass = "EXPIRED"
+
+	// ExpiryClassExpiringSoon means the certificate expires within the window.
+	ExpiryClassExpiringSoon ExpiryClass = "EXPIRING_SOON"
+
+	// ExpiryClassInUseNotRenewable means the certificate is in use, but is not eligible for the managed renewal.
+	ExpiryClassInUseNotRenewable ExpiryClass = "IN_USE_NOT_RENEWABLE"
+
+	// ExpiryClassImportedNotRenewable means the certificate is imported, and ACM never renews it.
+	ExpiryClassImportedNotRenewable ExpiryClass = "IMPORTED_NOT_RENEWABLE"
+
+	// ExpiryClassHealthy means the certificate does not expire within the window, and is renewed or unused.
+	ExpiryClassHealthy ExpiryClass = "HEALTHY"
+)
+
+// ExpiryClasses are the classes of expiry in order of urgency.
+var ExpiryClasses = []ExpiryClass{
+	ExpiryClassExpired,
+	ExpiryClassExpiringSoon,
+	ExpiryClassInUseNotRenewable,
+	ExpiryClassImportedNotRenewable,
+	ExpiryClassHealthy,
+}
+
+// CertificateExpiry is a structure that represents a certificate classified by its expiry.
+type CertificateExpiry struct {
+	Class ExpiryClass `json:"class" yaml:"class"`
+
+	// DaysLeft is the number of whole days until the certificate expires. It is negative if the certificate has expired.
+	DaysLeft    int         `json:"daysLeft" yaml:"daysLeft"`
+	Certificate Certificate `json:"certificate" yaml:"certificate"`
+}
+
+// ExpiryOptions is a structure that represents options for classifying certificates by their expiry.
+type ExpiryOptions struct {
+	// Window is a duration in which certificates are expiring soon. Default is DefaultExpiryWindow.
+	Window time.Duration
+
+	// Now is the time to classify certificates at. Default is the current time.
+	Now time.Time
+}
+
+// DaysLeft returns the number of whole days from now until notAfter, rounded down,
+// so that it is negative as soon as notAfter has passed.
+func DaysLeft(notAfter, now time.Time) int {
+	left := notAfter.Sub(now)
+	days := left / (24 * time.Hour)
+	if left < 0 && left%(24*time.Hour) != 0 {
+		days--
+	}
+	return int(days)
+}
+
+// ClassifyCertificateExpiry classifies the certificates by their expiry, and sorts them by urgency,
+// then by the time they expire. Certificates that have not been issued yet have no expiry and are skipped.
+// The certificates can be gathered from multiple accounts or regions.
+func ClassifyCertificateExpiry(cList []Certificate, optFns ...func(*ExpiryOptions)) []CertificateExpiry {
+	o := ExpiryOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.Window == 0 {
+		o.Window = DefaultExpiryWindow
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+
+	expiries := []CertificateExpiry{}
+	for _, c := range cList {
+		if c.NotAfter == nil {
+			continue
+		}
+
+		left := c.NotAfter.Sub(o.Now)
+		expiries = append(expiries, CertificateExpiry{
+			Class:       expiryClass(c, left, o.Window),
+			DaysLeft:    DaysLeft(*c.NotAfter, o.Now),
+			Certificate: c,
+		})
+	}
+
+	rank := map[ExpiryClass]int{}
+	for i, class := range ExpiryClasses {
+		rank[class] = i
+	}
+	sort.SliceStable(expiries, func(i, j int) bool {
+		if ri, rj := rank[expiries[i].Class], rank[expiries[j].Class]; ri != rj {
+			return ri < rj
+		}
+		return expiries[i].Certificate.NotAfter.Before(*expiries[j].Certificate.NotAfter)
+	})
+
+	return expiries
+}
+
+// Return the class of the certificate that expires after the duration left.
+func expiryClass(c Certificate, left, window time.Duration) ExpiryClass {
+	switch {
+	case left <= 0:
+		return ExpiryClassExpired
+	case left <= window:
+		return ExpiryClassExpiringSoon
+	case len(c.InUseBy) > 0 && c.RenewalEligibility == string(types.RenewalEligibilityIneligible):
+		return ExpiryClassInUseNotRenewable
+	case c.Type == string(types.CertificateTypeImported):
+		return ExpiryClassImportedNotRenewable
+	}
+	return ExpiryClassHealthy
+}
+
+// ListCertificateExpiries returns the certificates classified by their expiry in order of urgency.
+// If some certificates cannot be described, it returns the other certificates with *CertificatesError.
+func ListCertificateExpiries(ctx context.Context, api ACMAPI, optFns ...func(*ExpiryOptions)) ([]CertificateExpiry, error) {
+	cList, err := ListCertificates(ctx, api)
+	var ce *CertificatesError
+	if err != nil && !errors.As(err, &ce) {
+		return nil, err
+	}
+	return ClassifyCertificateExpiry(cList, optFns...), err
+}
+
+// ListCertificateExpiriesInRegions returns the certificates in all regions classified by their expiry in order of urgency.
+// If it fails in some regions, it returns certificates of the other regions with *RegionsError.
+func ListCertificateExpiriesInRegions(ctx context.Context, apis RegionalACMAPI, optFns ...func(*ExpiryOptions)) ([]CertificateExpiry, error) {
+	cList, err := ListCertificatesInRegions(ctx, apis)
+	return ClassifyCertificateExpiry(cList, optFns...), err
+}
+
+// ListCertificateExpiries returns the certificates classified by their expiry with the ACM client.
+func (g *GoACM) ListCertificateExpiries(ctx context.Context, optFns ...func(*ExpiryOptions)) ([]CertificateExpiry, error) {
+	return ListCertificateExpiries(ctx, g.ACMAPI(), optFns...)
+}
+
+// ListCertificateExpiries returns the certificates in all regions classified by their expiry.
+func (m *MultiRegionGoACM) ListCertificateExpiries(ctx context.Context, optFns ...func(*ExpiryOptions)) ([]CertificateExpiry, error) {
+	return ListCertificateExpiriesInRegions(ctx, m.ACMAPIs(), optFns...)
+}
@@ -0,0 +1,173 @@
+package goacm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClassifyCertificateExpiry(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) *time.Time {
+		return aws.Time(now.Add(time.Duration(n) * 24 * time.Hour))
+	}
+
+	expired := goacm.Certificate{Arn: "expired", Type: string(types.CertificateTypeAmazonIssued), NotAfter: days(-1)}
+	soon := goacm.Certificate{Arn: "soon", Type: string(types.CertificateTypeAmazonIssued), NotAfter: days(20)}
+	sooner := goacm.Certificate{Arn: "sooner", Type: string(types.CertificateTypeImported), NotAfter: days(10)}
+	inUse := goacm.Certificate{
+		Arn:                "in-use",
+		Type:               string(types.CertificateTypeAmazonIssued),
+		NotAfter:           days(60),
+		InUseBy:            []string{"arn:aws:elasticloadbalancing:ap-northeast-1:000000000000:loadbalancer/app/alb/0000"},
+		RenewalEligibility: string(types.RenewalEligibilityIneligible),
+	}
+	imported := goacm.Certificate{Arn: "imported", Type: string(types.CertificateTypeImported), NotAfter: days(90)}
+	healthy := goacm.Certificate{
+		Arn:                "healthy",
+		Type:               string(types.CertificateTypeAmazonIssued),
+		NotAfter:           days(300),
+		InUseBy:            []string{"arn:aws:cloudfront::000000000000:distribution/0000"},
+		RenewalEligibility: string(types.RenewalEligibilityEligible),
+	}
+	pending := goacm.Certificate{Arn: "pending", Status: string(types.CertificateStatusPendingValidation)}
+
+	cList := []goacm.Certificate{healthy, imported, pending, inUse, soon, expired, sooner}
+
+	cases := []struct {
+		name   string
+		window time.Duration
+		expect []goacm.CertificateExpiry
+	}{
+		{
+			name: "normal",
+			expect: []goacm.CertificateExpiry{
+				{Class: goacm.ExpiryClassExpired, DaysLeft: -1, Certificate: expired},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 10, Certificate: sooner},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 20, Certificate: soon},
+				{Class: goacm.ExpiryClassInUseNotRenewable, DaysLeft: 60, Certificate: inUse},
+				{Class: goacm.ExpiryClassImportedNotRenewable, DaysLeft: 90, Certificate: imported},
+				{Class: goacm.ExpiryClassHealthy, DaysLeft: 300, Certificate: healthy},
+			},
+		},
+		{
+			name:   "normal: narrow window",
+			window: 14 * 24 * time.Hour,
+			expect: []goacm.CertificateExpiry{
+				{Class: goacm.ExpiryClassExpired, DaysLeft: -1, Certificate: expired},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 10, Certificate: sooner},
+				{Class: goacm.ExpiryClassInUseNotRenewable, DaysLeft: 60, Certificate: inUse},
+				{Class: goacm.ExpiryClassImportedNotRenewable, DaysLeft: 90, Certificate: imported},
+				{Class: goacm.ExpiryClassHealthy, DaysLeft: 20, Certificate: soon},
+				{Class: goacm.ExpiryClassHealthy, DaysLeft: 300, Certificate: healthy},
+			},
+		},
+		{
+			name:   "normal: wide window",
+			window: 365 * 24 * time.Hour,
+			expect: []goacm.CertificateExpiry{
+				{Class: goacm.ExpiryClassExpired, DaysLeft: -1, Certificate: expired},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 10, Certificate: sooner},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 20, Certificate: soon},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 60, Certificate: inUse},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 90, Certificate: imported},
+				{Class: goacm.ExpiryClassExpiringSoon, DaysLeft: 300, Certificate: healthy},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			expiries := goacm.ClassifyCertificateExpiry(cList, func(o *goacm.ExpiryOptions) {
+				o.Window = c.window
+				o.Now = now
+			})
+
+			assert.Equal(tt, c.expect, expiries)
+		})
+	}
+}
+
+func Test_DaysLeft(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		notAfter time.Time
+		expect   int
+	}{
+		{
+			name:     "normal: expires in a day and an hour",
+			notAfter: now.Add(25 * time.Hour),
+			expect:   1,
+		},
+		{
+			name:     "normal: expires in an hour",
+			notAfter: now.Add(time.Hour),
+			expect:   0,
+		},
+		{
+			name:     "normal: expires now",
+			notAfter: now,
+			expect:   0,
+		},
+		{
+			name:     "normal: expired a second ago",
+			notAfter: now.Add(-time.Second),
+			expect:   -1,
+		},
+		{
+			name:     "normal: expired a day ago",
+			notAfter: now.Add(-24 * time.Hour),
+			expect:   -1,
+		},
+		{
+			name:     "normal: expired a day and an hour ago",
+			notAfter: now.Add(-25 * time.Hour),
+			expect:   -2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, goacm.DaysLeft(c.notAfter, now))
+		})
+	}
+}
+
+func Test_ListCertificateExpiries(t *testing.T) {
+	notAfter := time.Now().Add(10 * 24 * time.Hour)
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring",
+				DomainName: "expiring.example.com",
+				Status:     string(types.CertificateStatusIssued),
+				Type:       string(types.CertificateTypeAmazonIssued),
+				NotAfter:   aws.Time(notAfter),
+				InUseBy:    []string{"arn:aws:cloudfront::000000000000:distribution/0000"},
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/pending",
+				DomainName: "pending.example.com",
+				Status:     string(types.CertificateStatusPendingValidation),
+				Type:       string(types.CertificateTypeAmazonIssued),
+			},
+		},
+	}
+
+	expiries, err := goacm.ListCertificateExpiries(context.TODO(), goacm.NewMockACMAPI(ap))
+	assert.NoError(t, err)
+	if assert.Len(t, expiries, 1) {
+		assert.Equal(t, goacm.ExpiryClassExpiringSoon, expiries[0].Class)
+		assert.Equal(t, 9, expiries[0].DaysLeft)
+		assert.Equal(t, ap[0].Certificate, expiries[0].Certificate)
+	}
+}
@@ -1,3 +1,20 @@
 package goacm
 
+import "time"
+
 var ExportedGetPublicHostedZoneIDByDomainName = getPublicHostedZoneIDByDomainName
+
+var ExportedResolveConfigs = resolveConfigs
+
+var ExportedResolveAccountIDs = resolveAccountIDs
+
+var ExportedAllRegions = allRegions
+
+// SetValidationRecordInterval sets the interval of describing the certificate, and returns a function that restores it.
+func SetValidationRecordInterval(d time.Duration) func() {
+	org := validationRecordInterval
+	validationRecordInterval = d
+	return func() {
+		validationRecordInterval = org
+	}
+}
@@ -0,0 +1,350 @@
+// Package exporter exposes metrics of ACM certificates in the Prometheus text format.
+//
+// Certificates are listed in all regions of the ACM APIs and cached, so that frequent scrapes
+// do not exceed the rate limits of ACM. Metrics are labeled with the ARN, the domain name and the region.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+)
+
+// DefaultCacheTTL is a default duration for which listed certificates are cached.
+const DefaultCacheTTL = 5 * time.Minute
+
+// DefaultRefreshTimeout is a default timeout of refreshes started by scrapes.
+const DefaultRefreshTimeout = time.Minute
+
+// ContentType is the content type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Options is a structure that represents options for Exporter.
+type Options struct {
+	// CacheTTL is a duration for which listed certificates are cached. Default is DefaultCacheTTL.
+	CacheTTL time.Duration
+
+	// RefreshTimeout is a timeout of refreshes started by scrapes. Default is DefaultRefreshTimeout.
+	RefreshTimeout time.Duration
+
+	// Now returns the current time. Default is time.Now.
+	Now func() time.Time
+}
+
+// Exporter is a structure that lists certificates and writes their metrics.
+// It implements http.Handler to serve the metrics.
+type Exporter struct {
+	apis           goacm.RegionalACMAPI
+	cacheTTL       time.Duration
+	refreshTimeout time.Duration
+	now            func() time.Time
+
+	// refreshMu serializes refreshes, and mu guards the cache so that scrapes are not blocked by refreshes
+	refreshMu sync.Mutex
+
+	mu                 sync.Mutex
+	certificates       []goacm.Certificate
+	attemptedAt        time.Time
+	refreshedAt        time.Time
+	refreshErrors      int
+	lastSucceeded      bool
+	failedCertificates int
+	refreshing         chan struct{}
+}
+
+// New returns an Exporter of the certificates in the regions of the ACM APIs.
+func New(apis goacm.RegionalACMAPI, optFns ...func(*Options)) *Exporter {
+	o := Options{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.CacheTTL == 0 {
+		o.CacheTTL = DefaultCacheTTL
+	}
+	if o.RefreshTimeout == 0 {
+		o.RefreshTimeout = DefaultRefreshTimeout
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+
+	return &Exporter{
+		apis:           apis,
+		cacheTTL:       o.CacheTTL,
+		refreshTimeout: o.RefreshTimeout,
+		now:            o.Now,
+	}
+}
+
+// Refresh lists the certificates and replaces the cache.
+// If it fails in some regions, or some certificates cannot be described, the others are cached with
+// the certificates of the last refresh that could not be listed this time, and the error is returned.
+// If it fails in all regions, the cache is kept. If the context is canceled, the refresh is not counted,
+// so that the next scrape retries it. A refresh that times out is counted as failed.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	e.refreshMu.Lock()
+	defer e.refreshMu.Unlock()
+
+	attemptedAt := e.now()
+	cList, err := goacm.ListCertificatesInRegions(ctx, e.apis)
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.attemptedAt = attemptedAt
+
+	var re *goacm.RegionsError
+	if err != nil && (!errors.As(err, &re) || !listedAny(re, len(e.apis))) {
+		e.refreshErrors++
+		e.lastSucceeded = false
+		return err
+	}
+
+	failed := 0
+	if re != nil {
+		cList, failed = e.keepFailedCertificates(cList, re)
+	}
+
+	e.certificates = cList
+	e.refreshedAt = attemptedAt
+	e.lastSucceeded = err == nil
+	e.failedCertificates = failed
+	if err != nil {
+		e.refreshErrors++
+	}
+	return err
+}
+
+// Returns true if certificates were listed in any region.
+func listedAny(re *goacm.RegionsError, regions int) bool {
+	if len(re.Errors) < regions {
+		return true
+	}
+	for _, err := range re.Errors {
+		var ce *goacm.CertificatesError
+		if errors.As(err, &ce) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the listed certificates with the cached ones of the regions that failed and the certificates
+// that could not be described, and the number of the certificates that could not be described.
+func (e *Exporter) keepFailedCertificates(cList []goacm.Certificate, re *goacm.RegionsError) ([]goacm.Certificate, int) {
+	failed := 0
+	for _, err := range re.Errors {
+		var ce *goacm.CertificatesError
+		if errors.As(err, &ce) {
+			failed += len(ce.Errors)
+		}
+	}
+
+	for _, c := range e.certificates {
+		err, ok := re.Errors[c.Region]
+		if !ok {
+			continue
+		}
+		var ce *goacm.CertificatesError
+		if !errors.As(err, &ce) {
+			cList = append(cList, c)
+			continue
+		}
+		if _, ok := ce.Errors[c.Arn]; ok {
+			cList = append(cList, c)
+		}
+	}
+	return cList, failed
+}
+
+// Run refreshes the cache at every interval until the context is done.
+// Errors are passed to onError if it is not nil.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WriteMetrics writes the metrics of the cached certificates. The cache is refreshed if the last refresh,
+// successful or not, is older than the TTL. The refresh runs in the background with RefreshTimeout,
+// so that a scrape that times out does not cancel it, and the metrics are written when it finishes or the context is done.
+// Errors in refreshing are reported in the metrics of the exporter, and are not returned.
+func (e *Exporter) WriteMetrics(ctx context.Context, w io.Writer) error {
+	if done := e.refreshIfStale(); done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+
+	e.mu.Lock()
+	cList := e.certificates
+	refreshedAt := e.refreshedAt
+	refreshErrors := e.refreshErrors
+	lastSucceeded := e.lastSucceeded
+	failedCertificates := e.failedCertificates
+	e.mu.Unlock()
+
+	m := &metricsWriter{w: w}
+	now := e.now()
+
+	m.help("goacm_certificate_expiry_seconds", "gauge", "Seconds until the certificate expires. Negative if it has expired.")
+	for _, c := range cList {
+		if c.NotAfter != nil {
+			m.sample("goacm_certificate_expiry_seconds", certificateLabels(c), c.NotAfter.Sub(now).Seconds())
+		}
+	}
+
+	m.help("goacm_certificate_status", "gauge", "Status of the certificate. The value is 1 for the current status.")
+	for _, c := range cList {
+		m.sample("goacm_certificate_status", append(certificateLabels(c), label{"status", c.Status}), 1)
+	}
+
+	m.help("goacm_certificate_in_use", "gauge", "Number of AWS resources that use the certificate.")
+	for _, c := range cList {
+		m.sample("goacm_certificate_in_use", certificateLabels(c), float64(len(c.InUseBy)))
+	}
+
+	m.help("goacm_certificate_renewal_eligible", "gauge", "1 if the certificate is eligible for the managed renewal, otherwise 0.")
+	for _, c := range cList {
+		eligible := 0.0
+		if c.RenewalEligibility == string(types.RenewalEligibilityEligible) {
+			eligible = 1
+		}
+		m.sample("goacm_certificate_renewal_eligible", certificateLabels(c), eligible)
+	}
+
+	m.help("goacm_exporter_last_refresh_success", "gauge", "1 if the last refresh of certificates succeeded in all regions for all certificates, otherwise 0.")
+	m.sample("goacm_exporter_last_refresh_success", nil, boolValue(lastSucceeded))
+
+	m.help("goacm_exporter_last_refresh_failed_certificates", "gauge", "Number of certificates that could not be described in the last refresh.")
+	m.sample("goacm_exporter_last_refresh_failed_certificates", nil, float64(failedCertificates))
+
+	m.help("goacm_exporter_last_refresh_timestamp_seconds", "gauge", "Unix time of the last refresh of certificates.")
+	if !refreshedAt.IsZero() {
+		m.sample("goacm_exporter_last_refresh_timestamp_seconds", nil, float64(refreshedAt.Unix()))
+	}
+
+	m.help("goacm_exporter_refresh_errors_total", "counter", "Number of refreshes of certificates that failed.")
+	m.sample("goacm_exporter_refresh_errors_total", nil, float64(refreshErrors))
+
+	return m.err
+}
+
+// Starts a refresh in the background if the last refresh is older than the TTL and no refresh is running,
+// and returns a channel closed when the running refresh finishes, or nil if the cache is fresh.
+func (e *Exporter) refreshIfStale() <-chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.refreshing != nil {
+		return e.refreshing
+	}
+	if !e.attemptedAt.IsZero() && e.now().Sub(e.attemptedAt) < e.cacheTTL {
+		return nil
+	}
+
+	done := make(chan struct{})
+	e.refreshing = done
+	go func() {
+		defer close(done)
+
+		ctx, cancel := context.WithTimeout(context.Background(), e.refreshTimeout)
+		defer cancel()
+		_ = e.Refresh(ctx)
+
+		e.mu.Lock()
+		e.refreshing = nil
+		e.mu.Unlock()
+	}()
+	return done
+}
+
+// ServeHTTP writes the metrics. They are rendered before writing, so that an error is returned
+// as a status instead of a truncated body.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := e.WriteMetrics(r.Context(), &buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+func certificateLabels(c goacm.Certificate) []label {
+	return []label{
+		{"arn", c.Arn},
+		{"domain", c.DomainName},
+		{"region", c.Region},
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// metricsWriter writes metrics in the Prometheus text format, and keeps the first error.
+type metricsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (m *metricsWriter) printf(format string, a ...interface{}) {
+	if m.err != nil {
+		return
+	}
+	_, m.err = fmt.Fprintf(m.w, format, a...)
+}
+
+func (m *metricsWriter) help(name, typ, help string) {
+	m.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (m *metricsWriter) sample(name string, labels []label, value float64) {
+	if len(labels) == 0 {
+		m.printf("%s %g\n", name, value)
+		return
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+	pairs := make([]string, len(labels))
+	for i, l := range labels {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, l.name, labelEscaper.Replace(l.value))
+	}
+	m.printf("%s{%s} %g\n", name, strings.Join(pairs, ","), value)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,202 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/exporter"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var ap = []goacm.MockACMParams{
+	{
+		Certificate: goacm.Certificate{
+			Arn:                "arn:aws:acm:us-east-1:000000000000:certificate/issued",
+			DomainName:         "example.com",
+			Status:             string(types.CertificateStatusIssued),
+			NotAfter:           aws.Time(now.Add(24 * time.Hour)),
+			InUseBy:            []string{"arn:aws:cloudfront::000000000000:distribution/0000"},
+			RenewalEligibility: string(types.RenewalEligibilityEligible),
+		},
+	},
+	{
+		Certificate: goacm.Certificate{
+			Arn:        "arn:aws:acm:us-east-1:000000000000:certificate/pending",
+			DomainName: `"quoted".example.com`,
+			Status:     string(types.CertificateStatusPendingValidation),
+		},
+	},
+}
+
+func Test_Exporter_ServeHTTP(t *testing.T) {
+	expect := `# HELP goacm_certificate_expiry_seconds Seconds until the certificate expires. Negative if it has expired.
+# TYPE goacm_certificate_expiry_seconds gauge
+goacm_certificate_expiry_seconds{arn="arn:aws:acm:us-east-1:000000000000:certificate/issued",domain="example.com",region="us-east-1"} 86400
+# HELP goacm_certificate_status Status of the certificate. The value is 1 for the current status.
+# TYPE goacm_certificate_status gauge
+goacm_certificate_status{arn="arn:aws:acm:us-east-1:000000000000:certificate/issued",domain="example.com",region="us-east-1",status="ISSUED"} 1
+goacm_certificate_status{arn="arn:aws:acm:us-east-1:000000000000:certificate/pending",domain="\"quoted\".example.com",region="us-east-1",status="PENDING_VALIDATION"} 1
+# HELP goacm_certificate_in_use Number of AWS resources that use the certificate.
+# TYPE goacm_certificate_in_use gauge
+goacm_certificate_in_use{arn="arn:aws:acm:us-east-1:000000000000:certificate/issued",domain="example.com",region="us-east-1"} 1
+goacm_certificate_in_use{arn="arn:aws:acm:us-east-1:000000000000:certificate/pending",domain="\"quoted\".example.com",region="us-east-1"} 0
+# HELP goacm_certificate_renewal_eligible 1 if the certificate is eligible for the managed renewal, otherwise 0.
+# TYPE goacm_certificate_renewal_eligible gauge
+goacm_certificate_renewal_eligible{arn="arn:aws:acm:us-east-1:000000000000:certificate/issued",domain="example.com",region="us-east-1"} 1
+goacm_certificate_renewal_eligible{arn="arn:aws:acm:us-east-1:000000000000:certificate/pending",domain="\"quoted\".example.com",region="us-east-1"} 0
+# HELP goacm_exporter_last_refresh_success 1 if the last refresh of certificates succeeded in all regions for all certificates, otherwise 0.
+# TYPE goacm_exporter_last_refresh_success gauge
+goacm_exporter_last_refresh_success 1
+# HELP goacm_exporter_last_refresh_failed_certificates Number of certificates that could not be described in the last refresh.
+# TYPE goacm_exporter_last_refresh_failed_certificates gauge
+goacm_exporter_last_refresh_failed_certificates 0
+# HELP goacm_exporter_last_refresh_timestamp_seconds Unix time of the last refresh of certificates.
+# TYPE goacm_exporter_last_refresh_timestamp_seconds gauge
+goacm_exporter_last_refresh_timestamp_seconds 1.6409952e+09
+# HELP goacm_exporter_refresh_errors_total Number of refreshes of certificates that failed.
+# TYPE goacm_exporter_refresh_errors_total counter
+goacm_exporter_refresh_errors_total 0
+`
+
+	e := exporter.New(goacm.RegionalACMAPI{"us-east-1": goacm.NewMockACMAPI(ap)}, func(o *exporter.Options) {
+		o.Now = func() time.Time { return now }
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, exporter.ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, expect, string(body))
+}
+
+func Test_Exporter_WriteMetrics(t *testing.T) {
+	cases := []struct {
+		name           string
+		elapsed        time.Duration
+		fail           bool
+		describeFail   bool
+		expectListed   int
+		expectContains []string
+	}{
+		{
+			name:           "normal: cached",
+			elapsed:        time.Minute,
+			expectListed:   1,
+			expectContains: []string{`region="us-east-1"} 86340`},
+		},
+		{
+			name:           "normal: cache expired",
+			elapsed:        exporter.DefaultCacheTTL,
+			expectListed:   2,
+			expectContains: []string{"goacm_exporter_last_refresh_success 1"},
+		},
+		{
+			name:         "error: refresh failed",
+			elapsed:      exporter.DefaultCacheTTL,
+			fail:         true,
+			expectListed: 2,
+			expectContains: []string{
+				"goacm_exporter_last_refresh_success 0",
+				"goacm_exporter_refresh_errors_total 1",
+				// certificates of the last successful refresh are kept
+				`status="ISSUED"} 1`,
+			},
+		}, {
+			name:         "error: failed to describe a certificate",
+			elapsed:      exporter.DefaultCacheTTL,
+			describeFail: true,
+			expectListed: 2,
+			expectContains: []string{
+				"goacm_exporter_last_refresh_success 0",
+				"goacm_exporter_last_refresh_failed_certificates 1",
+				"goacm_exporter_refresh_errors_total 1",
+				// the certificate of the last refresh is kept
+				`status="ISSUED"} 1`,
+				`status="PENDING_VALIDATION"} 1`,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			clock := now
+			listed := 0
+			aAPI := goacm.NewMockACMAPI(ap)
+			list := aAPI.ListCertificatesAPI
+			aAPI.ListCertificatesAPI = func(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+				listed++
+				if c.fail && listed > 1 {
+					return nil, errors.New("throttled")
+				}
+				return list(ctx, params, optFns...)
+			}
+			describe := aAPI.DescribeCertificateAPI
+			aAPI.DescribeCertificateAPI = func(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+				if c.describeFail && listed > 1 && aws.ToString(params.CertificateArn) == ap[0].Certificate.Arn {
+					return nil, errors.New("throttled")
+				}
+				return describe(ctx, params, optFns...)
+			}
+
+			e := exporter.New(goacm.RegionalACMAPI{"us-east-1": aAPI}, func(o *exporter.Options) {
+				o.Now = func() time.Time { return clock }
+			})
+
+			assert.NoError(tt, e.Refresh(context.TODO()))
+			clock = clock.Add(c.elapsed)
+
+			rec := httptest.NewRecorder()
+			assert.NoError(tt, e.WriteMetrics(context.TODO(), rec))
+			assert.Equal(tt, c.expectListed, listed)
+			for _, s := range c.expectContains {
+				assert.Contains(tt, rec.Body.String(), s)
+			}
+		})
+	}
+}
+
+func Test_Exporter_WriteMetrics_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	listed := 0
+	aAPI := goacm.NewMockACMAPI(ap)
+	list := aAPI.ListCertificatesAPI
+	aAPI.ListCertificatesAPI = func(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+		<-release
+		listed++
+		// the refresh is not canceled with the scrape
+		assert.NoError(t, ctx.Err())
+		return list(ctx, params, optFns...)
+	}
+
+	e := exporter.New(goacm.RegionalACMAPI{"us-east-1": aAPI}, func(o *exporter.Options) {
+		o.Now = func() time.Time { return now }
+	})
+
+	// the scrape times out before the refresh finishes
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	rec := httptest.NewRecorder()
+	assert.NoError(t, e.WriteMetrics(ctx, rec))
+	assert.Contains(t, rec.Body.String(), "goacm_exporter_last_refresh_success 0")
+	assert.NotContains(t, rec.Body.String(), "goacm_certificate_status{")
+
+	// the next scrape waits for the running refresh instead of starting another one
+	close(release)
+	rec = httptest.NewRecorder()
+	assert.NoError(t, e.WriteMetrics(context.TODO(), rec))
+	assert.Equal(t, 1, listed)
+	assert.Contains(t, rec.Body.String(), "goacm_exporter_last_refresh_success 1")
+	assert.Contains(t, rec.Body.String(), `status="ISSUED"} 1`)
+}
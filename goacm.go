@@ -2,49 +2,136 @@ package goacm
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	"github.com/aws/aws-sdk-go-v2/service/acm/types"
 	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+const (
+	// defaultWaitInterval is an interval of polling in WaitCertificateIssued.
+	defaultWaitInterval = 15 * time.Second
+
+	// defaultWaitTimeout is a timeout of WaitCertificateIssued.
+	defaultWaitTimeout = time.Hour
+
+	// validationRecordRetries is a number of times to describe the certificate
+	// until the record that validates the domain is generated.
+	validationRecordRetries = 6
+)
+
+// validationRecordInterval is an interval of describing the certificate
+// until the record that validates the domain is generated.
+var validationRecordInterval = 5 * time.Second
+
 // GoACM is a structure that wraps an ACM client.
+// ACMAccountID and Route53AccountID are the IDs of the accounts the clients belong to,
+// resolved from the assumed roles or with STS GetCallerIdentity, and are empty if unknown.
 type GoACM struct {
-	ACMClient     *acm.Client
-	Route53Client *route53.Client
-	Region        string
+	ACMClient        *acm.Client
+	Route53Client    *route53.Client
+	Region           string
+	ACMAccountID     string
+	Route53AccountID string
 }
 
 // NewGoACM returns a new GoACM object.
-func NewGoACM(ctx context.Context, region string) (*GoACM, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+// The clients are created from the default config unless options change it.
+func NewGoACM(ctx context.Context, region string, optFns ...func(*GoACMOptions)) (*GoACM, error) {
+	o := GoACMOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	acmCfg, route53Cfg, err := resolveConfigs(ctx, region, o)
 	if err != nil {
 		return nil, err
 	}
 
+	acmAccountID, route53AccountID := resolveAccountIDs(ctx, sts.NewFromConfig(acmCfg), o)
+
 	return &GoACM{
-		ACMClient:     acm.NewFromConfig(cfg),
-		Route53Client: route53.NewFromConfig(cfg),
-		Region:        region,
+		ACMClient:        acm.NewFromConfig(acmCfg, acmClientOptions(o)...),
+		Route53Client:    route53.NewFromConfig(route53Cfg, route53ClientOptions(o)...),
+		Region:           acmCfg.Region,
+		ACMAccountID:     acmAccountID,
+		Route53AccountID: route53AccountID,
 	}, nil
 }
 
+// ACMAPI returns the ACM client that returns errors as ServiceError with the account ID.
+func (g *GoACM) ACMAPI() ACMAPI {
+	return NewAccountACMAPI(g.ACMClient, g.ACMAccountID)
+}
+
+// Route53API returns the Route 53 client that returns errors as ServiceError with the account ID.
+func (g *GoACM) Route53API() Route53API {
+	return NewAccountRoute53API(g.Route53Client, g.Route53AccountID)
+}
+
+// IssueCertificate issues an SSL certificate with the ACM client,
+// and validates the domain with the Route 53 client that may belong to another account.
+func (g *GoACM) IssueCertificate(ctx context.Context, method, targetDomain, hostedDomain string, optFns ...func(*IssueCertificateOptions)) (IssueCertificateResult, error) {
+	return IssueCertificate(ctx, g.ACMAPI(), g.Route53API(), method, targetDomain, hostedDomain, optFns...)
+}
+
+// IssuePrivateCertificate issues a private certificate from the AWS Private CA with the ACM client.
+func (g *GoACM) IssuePrivateCertificate(ctx context.Context, certificateAuthorityArn, targetDomain string, optFns ...func(*IssueCertificateOptions)) (IssueCertificateResult, error) {
+	return IssuePrivateCertificate(ctx, g.ACMAPI(), certificateAuthorityArn, targetDomain, optFns...)
+}
+
+// UpdateCertificateTransparencyLogging changes the certificate transparency logging preference of the certificate.
+func (g *GoACM) UpdateCertificateTransparencyLogging(ctx context.Context, arn, preference string) error {
+	return UpdateCertificateTransparencyLogging(ctx, g.ACMAPI(), arn, preference)
+}
+
+// DeleteCertificate deletes the certificate with the ACM client,
+// and the record set that validates the domain with the Route 53 client.
+func (g *GoACM) DeleteCertificate(ctx context.Context, arn string, optFns ...func(*DeleteCertificateOptions)) error {
+	return DeleteCertificate(ctx, g.ACMAPI(), g.Route53API(), arn, optFns...)
+}
+
+// DeleteCertificateWithLog deletes the certificate like DeleteCertificate, and returns the steps it made.
+func (g *GoACM) DeleteCertificateWithLog(ctx context.Context, arn string, optFns ...func(*DeleteCertificateOptions)) (DeleteLog, error) {
+	return DeleteCertificateWithLog(ctx, g.ACMAPI(), g.Route53API(), arn, optFns...)
+}
+
 // ListCertificateSummaries returns a list of certificate summary.
+// Certificates of all key algorithms are listed, while ACM lists only RSA certificates by default.
 func ListCertificateSummaries(ctx context.Context, api ACMListCertificatesAPI) ([]acmTypes.CertificateSummary, error) {
-	in := acm.ListCertificatesInput{}
-	out, err := api.ListCertificates(ctx, &in)
-	if err != nil {
-		return nil, err
+	in := acm.ListCertificatesInput{
+		Includes: &acmTypes.Filters{
+			KeyTypes: acmTypes.KeyAlgorithm("").Values(),
+		},
 	}
 
-	return out.CertificateSummaryList, nil
+	var summaries []acmTypes.CertificateSummary
+	for {
+		out, err := api.ListCertificates(ctx, &in)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, out.CertificateSummaryList...)
+
+		if aws.ToString(out.NextToken) == "" {
+			return summaries, nil
+		}
+		in.NextToken = out.NextToken
+	}
 }
 
 // GetCertificate returns the details of the certificate.
@@ -57,186 +144,910 @@ func GetCertificate(ctx context.Context, api ACMDescribeCertificateAPI, arn stri
 		return Certificate{}, err
 	}
 
+	return toCertificate(arn, out.Certificate), nil
+}
+
+// Convert the details of the certificate to Certificate.
+func toCertificate(arn string, d *types.CertificateDetail) Certificate {
 	vMethod := ""
 	recordSet := RecordSet{}
-	if out.Certificate.DomainValidationOptions != nil {
-		vMethod = string(out.Certificate.DomainValidationOptions[0].ValidationMethod)
-		if vMethod == string(types.ValidationMethodDns) {
-			recordSet.HostedDomainName = aws.ToString(out.Certificate.DomainValidationOptions[0].ValidationDomain)
-			recordSet.Name = aws.ToString(out.Certificate.DomainValidationOptions[0].ResourceRecord.Name)
-			recordSet.Value = aws.ToString(out.Certificate.DomainValidationOptions[0].ResourceRecord.Value)
-			recordSet.Type = string(out.Certificate.DomainValidationOptions[0].ResourceRecord.Type)
+	if d.DomainValidationOptions != nil {
+		vMethod = string(d.DomainValidationOptions[0].ValidationMethod)
+		if rs := validationRecordSets(d); len(rs) > 0 {
+			recordSet = rs[0]
+		}
+	}
+
+	// SubjectAlternativeNames of ACM contain the domain name of the certificate.
+	var sans []string
+	for _, n := range d.SubjectAlternativeNames {
+		if n != aws.ToString(d.DomainName) {
+			sans = append(sans, n)
 		}
 	}
 
+	transparencyLogging := ""
+	if d.Options != nil {
+		transparencyLogging = string(d.Options.CertificateTransparencyLoggingPreference)
+	}
+
 	return Certificate{
-		Arn:                 arn,
-		DomainName:          aws.ToString(out.Certificate.DomainName),
-		Status:              string(out.Certificate.Status),
-		Type:                string(out.Certificate.Type),
-		FailureReason:       string(out.Certificate.FailureReason),
-		ValidationMethod:    vMethod,
-		ValidationRecordSet: recordSet,
-	}, nil
+		Arn:                     arn,
+		DomainName:              aws.ToString(d.DomainName),
+		SubjectAlternativeNames: sans,
+		Status:                  string(d.Status),
+		Type:                    string(d.Type),
+		CertificateAuthorityArn: aws.ToString(d.CertificateAuthorityArn),
+		KeyAlgorithm:            string(d.KeyAlgorithm),
+		TransparencyLogging:     transparencyLogging,
+		RenewalEligibility:      string(d.RenewalEligibility),
+		CreatedAt:               d.CreatedAt,
+		NotAfter:                d.NotAfter,
+		InUseBy:                 d.InUseBy,
+		FailureReason:           string(d.FailureReason),
+		ValidationMethod:        vMethod,
+		ValidationRecordSet:     recordSet,
+		ValidationEmails:        validationEmails(d),
+	}
+}
+
+// Return the distinct records that validate the domains of the certificate by DNS.
+// Domains such as "example.com" and "*.example.com" share a record.
+func validationRecordSets(d *types.CertificateDetail) []RecordSet {
+	var rsList []RecordSet
+	for _, dv := range d.DomainValidationOptions {
+		if dv.ValidationMethod != types.ValidationMethodDns || dv.ResourceRecord == nil {
+			continue
+		}
+
+		rs := RecordSet{
+			HostedDomainName: aws.ToString(dv.ValidationDomain),
+			Name:             aws.ToString(dv.ResourceRecord.Name),
+			Value:            aws.ToString(dv.ResourceRecord.Value),
+			Type:             string(dv.ResourceRecord.Type),
+		}
+		if !containsRecordSet(rsList, rs) {
+			rsList = append(rsList, rs)
+		}
+	}
+	return rsList
+}
+
+// Return the distinct approver addresses of the domains of the certificate validated by email.
+func validationEmails(d *types.CertificateDetail) []string {
+	var emails []string
+	seen := map[string]bool{}
+	for _, dv := range d.DomainValidationOptions {
+		for _, e := range dv.ValidationEmails {
+			if !seen[e] {
+				seen[e] = true
+				emails = append(emails, e)
+			}
+		}
+	}
+	return emails
+}
+
+func containsRecordSet(rsList []RecordSet, rs RecordSet) bool {
+	for _, r := range rsList {
+		if r.Name == rs.Name && r.Value == rs.Value {
+			return true
+		}
+	}
+	return false
 }
 
 // ListCertificates returns list of certificate.
+// If some certificates cannot be described, it returns the other certificates with *CertificatesError.
 func ListCertificates(ctx context.Context, api ACMAPI) ([]Certificate, error) {
 	summary, err := ListCertificateSummaries(ctx, api)
 	if err != nil {
 		return nil, err
 	}
 
+	return describeCertificates(ctx, api, summary)
+}
+
+// Describe the certificates of the summaries. If some certificates cannot be described,
+// return the other certificates with *CertificatesError.
+func describeCertificates(ctx context.Context, api ACMAPI, summaries []acmTypes.CertificateSummary) ([]Certificate, error) {
 	var cList []Certificate
-	for _, s := range summary {
-		c, err := GetCertificate(ctx, api, aws.ToString(s.CertificateArn))
+	errs := map[string]error{}
+	for _, s := range summaries {
+		arn := aws.ToString(s.CertificateArn)
+		c, err := GetCertificate(ctx, api, arn)
 		if err != nil {
-			fmt.Println(err.Error())
+			errs[arn] = err
 			continue
 		}
 		cList = append(cList, c)
 	}
 
+	if len(errs) > 0 {
+		return cList, &CertificatesError{Errors: errs}
+	}
+
 	return cList, nil
 }
 
-// DeleteCertificate returns an error if deleting the certificate fails.
-func DeleteCertificate(ctx context.Context, aAPI ACMAPI, rAPI Route53API, arn string) error {
-	c, err := GetCertificate(ctx, aAPI, arn)
+// DeleteCertificateOptions is a structure that represents options for DeleteCertificate.
+type DeleteCertificateOptions struct {
+	// Force deletes the certificate even if InUseBy is not empty, such as right after detaching it from the resources
+	// before ACM updates InUseBy. The certificate is deleted before the records, so that the records are kept
+	// if ACM refuses to delete the certificate still in use.
+	Force bool
+
+	// Regions are the ACM clients of the regions whose certificates can share the records with the certificate,
+	// such as replicas. The region of the certificate is always checked. Default is only the region of the certificate.
+	Regions RegionalACMAPI
+}
+
+// Returns the ACM clients of the regions whose certificates can share the records with the certificate of the ARN.
+func (o DeleteCertificateOptions) usageAPIs(aAPI ACMAPI, arn string) []ACMAPI {
+	apis := []ACMAPI{}
+	if _, ok := o.Regions[arnRegion(arn)]; !ok {
+		apis = append(apis, aAPI)
+	}
+	regions := make([]string, 0, len(o.Regions))
+	for r := range o.Regions {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+	for _, r := range regions {
+		apis = append(apis, o.Regions[r])
+	}
+	return apis
+}
+
+// Returns the records that validate the domains of the certificates listed by the ACM clients,
+// except the certificates of the ARNs. A record is the same for the certificates of a domain in all regions,
+// so it must not be deleted while any of them exists. Only the certificates that can share a record
+// of the domains are described, and certificates deleted after being listed are skipped.
+// The regions are scanned concurrently.
+func usedValidationRecords(ctx context.Context, apis []ACMAPI, except map[string]bool, domains []string) (map[RecordSet]bool, error) {
+	wanted := map[string]bool{}
+	for _, d := range domains {
+		wanted[validationDomain(d)] = true
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		used = map[RecordSet]bool{}
+		errs = make([]error, len(apis))
+	)
+	for i, api := range apis {
+		wg.Add(1)
+		go func(i int, api ACMAPI) {
+			defer wg.Done()
+
+			rsList, err := usedValidationRecordsInRegion(ctx, api, except, wanted)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, rs := range rsList {
+				used[rs] = true
+			}
+		}(i, api)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return used, nil
+}
+
+// Returns the records of the certificates listed by the ACM client that can share a record of the domains.
+func usedValidationRecordsInRegion(ctx context.Context, api ACMAPI, except, domains map[string]bool) ([]RecordSet, error) {
+	summaries, err := ListCertificateSummaries(ctx, api)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var rsList []RecordSet
+	for _, s := range summaries {
+		if except[aws.ToString(s.CertificateArn)] || !sharesValidationDomain(s, domains) {
+			continue
+		}
+		out, err := api.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: s.CertificateArn})
+		var nfe *acmTypes.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			// the certificate has been deleted after being listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rsList = append(rsList, validationRecordSets(out.Certificate)...)
+	}
+	return rsList, nil
+}
+
+// Returns whether the certificate of the summary can be validated with a record of the domains.
+// The summary does not have all subject alternative names of a certificate with many of them,
+// so such a certificate can always share the records.
+func sharesValidationDomain(s acmTypes.CertificateSummary, domains map[string]bool) bool {
+	if aws.ToBool(s.HasAdditionalSubjectAlternativeNames) {
+		return true
+	}
+	for _, d := range append([]string{aws.ToString(s.DomainName)}, s.SubjectAlternativeNameSummaries...) {
+		if domains[validationDomain(d)] {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the domain name and the subject alternative names of the certificate.
+func certificateDomains(d *types.CertificateDetail) []string {
+	return append([]string{aws.ToString(d.DomainName)}, d.SubjectAlternativeNames...)
+}
+
+// DeleteAction is a type that represents a step of deleting a certificate.
+type DeleteAction string
+
+// Steps of deleting certificates.
+const (
+	DeleteActionDelete  DeleteAction = "DELETE"
+	DeleteActionRestore DeleteAction = "RESTORE"
+	DeleteActionKeep    DeleteAction = "KEEP"
+)
+
+// DeleteStep is a structure that represents a change to ACM or Route 53 that DeleteCertificateWithLog made or tried.
+type DeleteStep struct {
+	// Service is ServiceACM or ServiceRoute53.
+	Service string       `json:"service" yaml:"service"`
+	Action  DeleteAction `json:"action" yaml:"action"`
+
+	// CertificateArn is set for changes to ACM.
+	CertificateArn string `json:"certificateArn,omitempty" yaml:"certificateArn,omitempty"`
+
+	// HostedZoneID and RecordSet are set for changes to Route 53.
+	HostedZoneID string    `json:"hostedZoneId,omitempty" yaml:"hostedZoneId,omitempty"`
+	RecordSet    RecordSet `json:"recordSet,omitempty" yaml:"recordSet,omitempty"`
+
+	// Error is the message of the error if the step failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// DeleteLog is a structure that represents the steps DeleteCertificateWithLog made in order,
+// including the records kept because other certificates use them,
+// and the records restored after ACM failed to delete the certificate.
+type DeleteLog struct {
+	Steps []DeleteStep `json:"steps" yaml:"steps"`
+}
+
+func (l *DeleteLog) add(s DeleteStep, err error) {
+	if err != nil {
+		s.Error = err.Error()
+	}
+	l.Steps = append(l.Steps, s)
+}
+
+// DeleteCertificate deletes the certificate and the records that validate the domains.
+// Records that other certificates use are kept, and records that do not exist are regarded as deleted.
+// If the certificate is in use, it returns *CertificateInUseError without deleting anything.
+// If ACM fails to delete the certificate, the deleted records are restored.
+func DeleteCertificate(ctx context.Context, aAPI ACMAPI, rAPI Route53API, arn string, optFns ...func(*DeleteCertificateOptions)) error {
+	_, err := DeleteCertificateWithLog(ctx, aAPI, rAPI, arn, optFns...)
+	return err
+}
+
+// DeleteCertificateWithLog deletes the certificate like DeleteCertificate, and returns the steps it made.
+// The records are deleted before the certificate, and restored with their values and TTLs
+// if deleting the rest of the records or the certificate fails, so that the certificate is never left
+// without the records that validate it. With Force, the certificate is deleted first,
+// and the log shows the records that are left if deleting them fails.
+func DeleteCertificateWithLog(ctx context.Context, aAPI ACMAPI, rAPI Route53API, arn string, optFns ...func(*DeleteCertificateOptions)) (DeleteLog, error) {
+	o := DeleteCertificateOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	log := DeleteLog{Steps: []DeleteStep{}}
+	in := acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+	out, err := aAPI.DescribeCertificate(ctx, &in)
+	if err != nil {
+		return log, err
+	}
+
+	if !o.Force {
+		if err := inUseError(arn, out.Certificate.InUseBy); err != nil {
+			return log, err
+		}
+	}
+
+	// Find the records to delete, and the records other certificates use, before deleting anything.
+	found := []deletedRecordSet{}
+	for _, rs := range validationRecordSets(out.Certificate) {
+		d, err := findRoute53RecordSet(ctx, rAPI, rs)
+		if errors.Is(err, errRecordSetNotFound) {
+			// the record was never created or has been deleted with another certificate
+			continue
+		}
+		if err != nil {
+			return log, err
+		}
+		found = append(found, d)
+	}
+	used := map[RecordSet]bool{}
+	if len(found) > 0 {
+		used, err = usedValidationRecords(ctx, o.usageAPIs(aAPI, arn), map[string]bool{arn: true}, certificateDomains(out.Certificate))
+		if err != nil {
+			return log, err
+		}
+	}
+
+	acmStep := DeleteStep{Service: ServiceACM, Action: DeleteActionDelete, CertificateArn: arn}
+	if o.Force {
+		err := deleteACMCertificate(ctx, aAPI, arn)
+		log.add(acmStep, err)
+		if err != nil {
+			return log, err
+		}
+	}
+
+	// Delete Route 53 Records that validate the domains.
+	deleted := []deletedRecordSet{}
+	for _, d := range found {
+		if used[d.recordSet] {
+			log.add(DeleteStep{Service: ServiceRoute53, Action: DeleteActionKeep, RecordSet: d.recordSet}, nil)
+			continue
+		}
+
+		err := changeRecordSet(ctx, rAPI, d.hostedZoneID, route53Types.ChangeActionDelete, d.resourceRecordSet)
+		log.add(DeleteStep{Service: ServiceRoute53, Action: DeleteActionDelete, HostedZoneID: d.hostedZoneID, RecordSet: d.recordSet}, err)
+		if err != nil {
+			if o.Force {
+				return log, err
+			}
+			return log, restoreRecordSets(ctx, rAPI, &log, deleted, err)
+		}
+		deleted = append(deleted, d)
+	}
+
+	if o.Force {
+		return log, nil
+	}
+
+	err = deleteACMCertificate(ctx, aAPI, arn)
+	log.add(acmStep, err)
+	if err != nil {
+		return log, restoreRecordSets(ctx, rAPI, &log, deleted, err)
 	}
+	return log, nil
+}
 
-	// Delete Route 53 Record that validate domain.
-	if c.ValidationMethod == string(types.ValidationMethodDns) {
-		if err := DeleteRoute53RecordSet(ctx, aAPI, rAPI, c.ValidationRecordSet); err != nil {
-			return err
+// Restores the deleted records, and returns the error that caused the restore,
+// with the records that could not be restored.
+func restoreRecordSets(ctx context.Context, rAPI Route53API, log *DeleteLog, deleted []deletedRecordSet, cause error) error {
+	failed := []string{}
+	for _, d := range deleted {
+		err := changeRecordSet(ctx, rAPI, d.hostedZoneID, route53Types.ChangeActionUpsert, d.resourceRecordSet)
+		log.add(DeleteStep{Service: ServiceRoute53, Action: DeleteActionRestore, HostedZoneID: d.hostedZoneID, RecordSet: d.recordSet}, err)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", d.recordSet.Name, err))
 		}
 	}
 
+	if len(failed) > 0 {
+		return fmt.Errorf("%w (failed to restore records: %s)", cause, strings.Join(failed, "; "))
+	}
+	return cause
+}
+
+// Delete only the certificate in ACM.
+func deleteACMCertificate(ctx context.Context, api ACMDeleteCertificateAPI, arn string) error {
 	in := acm.DeleteCertificateInput{
 		CertificateArn: aws.String(arn),
 	}
 
-	if _, err := aAPI.DeleteCertificate(ctx, &in); err != nil {
+	if _, err := api.DeleteCertificate(ctx, &in); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// RequestableKeyAlgorithms are key algorithms that ACM supports in requesting certificates.
+// Other algorithms are only supported in importing certificates.
+var RequestableKeyAlgorithms = []string{
+	string(types.KeyAlgorithmRsa2048),
+	string(types.KeyAlgorithmEcPrime256v1),
+	string(types.KeyAlgorithmEcSecp384r1),
+}
+
+// ValidateKeyAlgorithm returns an error if the key algorithm is not supported in requesting certificates.
+// An empty key algorithm is valid, and means RSA_2048.
+func ValidateKeyAlgorithm(keyAlgorithm string) error {
+	if keyAlgorithm == "" {
+		return nil
+	}
+	for _, a := range RequestableKeyAlgorithms {
+		if a == keyAlgorithm {
+			return nil
+		}
+	}
+	return fmt.Errorf("key algorithm is not supported in requesting certificates: %s (supported: %s)", keyAlgorithm, strings.Join(RequestableKeyAlgorithms, ", "))
+}
+
+// UpdateCertificateTransparencyLogging changes the certificate transparency logging preference
+// of the certificate to ENABLED or DISABLED. It takes effect when the certificate is renewed.
+func UpdateCertificateTransparencyLogging(ctx context.Context, api ACMUpdateCertificateOptionsAPI, arn, preference string) error {
+	if preference == "" {
+		return errors.New("transparency logging preference is required")
+	}
+	if err := ValidateTransparencyLogging(preference); err != nil {
+		return err
+	}
+
+	in := acm.UpdateCertificateOptionsInput{
+		CertificateArn: aws.String(arn),
+		Options: &acmTypes.CertificateOptions{
+			CertificateTransparencyLoggingPreference: acmTypes.CertificateTransparencyLoggingPreference(preference),
+		},
+	}
+	_, err := api.UpdateCertificateOptions(ctx, &in)
+	return err
+}
+
+// ValidateTransparencyLogging returns an error if the certificate transparency logging preference
+// is neither ENABLED nor DISABLED. An empty preference is valid, and means ENABLED.
+func ValidateTransparencyLogging(preference string) error {
+	switch acmTypes.CertificateTransparencyLoggingPreference(preference) {
+	case "", acmTypes.CertificateTransparencyLoggingPreferenceEnabled, acmTypes.CertificateTransparencyLoggingPreferenceDisabled:
+		return nil
+	}
+	return fmt.Errorf("invalid transparency logging preference: %s (supported: ENABLED, DISABLED)", preference)
+}
+
+var idempotencyTokenPattern = regexp.MustCompile(`^\w{1,32}$`)
+
+// Validate the options, and set the idempotency token derived from the request if it is empty.
+func (o *IssueCertificateOptions) prepare(method, targetDomain, hostedDomain string) error {
+	if err := ValidateKeyAlgorithm(o.KeyAlgorithm); err != nil {
+		return err
+	}
+	if err := ValidateTransparencyLogging(o.TransparencyLogging); err != nil {
+		return err
+	}
+
+	if o.IdempotencyToken == "" {
+		o.IdempotencyToken = idempotencyToken(method, targetDomain, hostedDomain, *o)
+	} else if !idempotencyTokenPattern.MatchString(o.IdempotencyToken) {
+		return fmt.Errorf("invalid idempotency token, use 1 to 32 letters, digits or underscores: %s", o.IdempotencyToken)
+	}
+	return nil
+}
+
+// Returns a token derived from the request, so that the same request has the same token.
+func idempotencyToken(method, targetDomain, hostedDomain string, o IssueCertificateOptions) string {
+	keys := make([]string, 0, len(o.Tags))
+	for k := range o.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, v := range []string{method, targetDomain, hostedDomain, o.KeyAlgorithm, o.TransparencyLogging} {
+		fmt.Fprintf(h, "%s\n", v)
+	}
+	for _, n := range normalizeNames(targetDomain, o.SubjectAlternativeNames) {
+		fmt.Fprintf(h, "san:%s\n", n)
+	}
+	for _, k := range keys {
+		fmt.Fprintf(h, "tag:%q=%q\n", k, o.Tags[k])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
 // IssueCertificate issues an SSL certificate for the specified domain.
-func IssueCertificate(ctx context.Context, aAPI ACMAPI, rAPI Route53API, method, targetDomain, hostedDomain string) (IssueCertificateResult, error) {
+// The subject alternative names in the options must belong to the hosted domain.
+func IssueCertificate(ctx context.Context, aAPI ACMAPI, rAPI Route53API, method, targetDomain, hostedDomain string, optFns ...func(*IssueCertificateOptions)) (IssueCertificateResult, error) {
+	o := IssueCertificateOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	derived := o.IdempotencyToken == ""
+	if err := o.prepare(method, targetDomain, hostedDomain); err != nil {
+		return IssueCertificateResult{}, err
+	}
+
+	// request certificate
+	arn, existed, err := requestIdempotentCertificate(ctx, aAPI, &o, derived, func(o IssueCertificateOptions) (string, error) {
+		return requestCertificate(ctx, aAPI, method, targetDomain, hostedDomain, o)
+	})
+	if err != nil {
+		return IssueCertificateResult{}, err
+	}
+
 	var result IssueCertificateResult = IssueCertificateResult{
+		CertificateArn:   arn,
 		DomainName:       targetDomain,
 		HostedDomainName: hostedDomain,
 		ValidationMethod: string(method),
+		KeyAlgorithm:     o.KeyAlgorithm,
+		IdempotencyToken: o.IdempotencyToken,
+		AlreadyExisted:   existed,
+	}
+	if result.KeyAlgorithm == "" {
+		result.KeyAlgorithm = string(types.KeyAlgorithmRsa2048)
 	}
 
-	// request certificate
-	reqIn := acm.RequestCertificateInput{
-		DomainName:       aws.String(targetDomain),
-		ValidationMethod: acmTypes.ValidationMethod(method),
-		DomainValidationOptions: []acmTypes.DomainValidationOption{
-			{
-				DomainName:       aws.String(targetDomain),
-				ValidationDomain: aws.String(hostedDomain),
-			},
-		},
+	if method == string(types.ValidationMethodEmail) {
+		return result, nil
 	}
-	r, err := aAPI.RequestCertificate(ctx, &reqIn)
+
+	vRecords, err := describeValidationRecords(ctx, aAPI, arn)
 	if err != nil {
-		return IssueCertificateResult{}, err
+		return IssueCertificateResult{}, rollbackError(ctx, aAPI, rAPI, result, err.Error())
 	}
 
-	result.CertificateArn = aws.ToString(r.CertificateArn)
+	result.ValidationRecordName = vRecords[0].Name
+	result.ValidationRecordValue = vRecords[0].Value
 
-	if method == string(types.ValidationMethodEmail) {
-		return result, nil
+	// allowed only public hosted zones
+	hzID, err := getPublicHostedZoneIDByDomainName(ctx, rAPI, hostedDomain)
+	if err != nil {
+		return IssueCertificateResult{}, rollbackError(ctx, aAPI, rAPI, result, err.Error())
 	}
 
-	time.Sleep(time.Second * 5)
+	if hzID == "" {
+		errMsg := fmt.Sprintf("Cannot get public hosted zone ID of %s in %s", hostedDomain, apiLabel(rAPI, ServiceRoute53))
+		return IssueCertificateResult{}, rollbackError(ctx, aAPI, rAPI, result, errMsg)
+	}
 
-	dcIn := acm.DescribeCertificateInput{
-		CertificateArn: r.CertificateArn,
+	result.HosteZoneID = hzID
+
+	for _, rs := range vRecords {
+		if _, err := createValidationRecord(ctx, rAPI, hzID, rs.Name, rs.Value); err != nil {
+			return IssueCertificateResult{}, rollbackError(ctx, aAPI, rAPI, result, err.Error())
+		}
 	}
-	c, err := aAPI.DescribeCertificate(ctx, &dcIn)
-	if err != nil {
+
+	return result, nil
+}
+
+// IssuePrivateCertificate issues a private certificate for the specified domain from the AWS Private CA.
+// Private certificates are not validated, so Route 53 is not used at all.
+// The certificate and its private key can be exported with ExportCertificate after it is issued.
+func IssuePrivateCertificate(ctx context.Context, aAPI ACMAPI, certificateAuthorityArn, targetDomain string, optFns ...func(*IssueCertificateOptions)) (IssueCertificateResult, error) {
+	o := IssueCertificateOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if err := validateCertificateAuthorityArn(certificateAuthorityArn); err != nil {
 		return IssueCertificateResult{}, err
 	}
-	if c.Certificate.DomainValidationOptions == nil {
-		errMsg := "DomainValidationOptions dose not exists"
-		if err := RollbackIssueCertificate(ctx, aAPI, rAPI, *c.Certificate.CertificateArn); err != nil {
-			errMsg += fmt.Sprintf("; Failed to rollback to issue certificate: %v", err)
-		} else {
-			errMsg += "; rollbacked to issue certificate"
+	// the CA takes the place of the validation method in deriving the token
+	derived := o.IdempotencyToken == ""
+	if err := o.prepare(certificateAuthorityArn, targetDomain, ""); err != nil {
+		return IssueCertificateResult{}, err
+	}
+
+	arn, existed, err := requestIdempotentCertificate(ctx, aAPI, &o, derived, func(o IssueCertificateOptions) (string, error) {
+		reqIn := acm.RequestCertificateInput{
+			DomainName:              aws.String(targetDomain),
+			CertificateAuthorityArn: aws.String(certificateAuthorityArn),
 		}
-		return IssueCertificateResult{}, errors.New(errMsg)
+		return sendRequestCertificate(ctx, aAPI, &reqIn, o)
+	})
+	if err != nil {
+		return IssueCertificateResult{}, err
 	}
 
-	vRecordName := c.Certificate.DomainValidationOptions[0].ResourceRecord.Name
-	vRecordValue := c.Certificate.DomainValidationOptions[0].ResourceRecord.Value
+	result := IssueCertificateResult{
+		CertificateArn:          arn,
+		DomainName:              targetDomain,
+		CertificateAuthorityArn: certificateAuthorityArn,
+		KeyAlgorithm:            o.KeyAlgorithm,
+		IdempotencyToken:        o.IdempotencyToken,
+		AlreadyExisted:          existed,
+	}
+	if result.KeyAlgorithm == "" {
+		result.KeyAlgorithm = string(types.KeyAlgorithmRsa2048)
+	}
 
-	result.ValidationRecordName = *vRecordName
-	result.ValidationRecordValue = *vRecordValue
+	return result, nil
+}
 
-	// allowed only public hosted zones
-	hzID, err := getPublicHostedZoneIDByDomainName(ctx, rAPI, hostedDomain)
+// Returns an error if the ARN is not of a certificate authority of AWS Private CA.
+func validateCertificateAuthorityArn(s string) error {
+	a, err := arn.Parse(s)
 	if err != nil {
-		errMsg := err.Error()
-		if err := RollbackIssueCertificate(ctx, aAPI, rAPI, *c.Certificate.CertificateArn); err != nil {
-			errMsg += fmt.Sprintf("; Failed to rollback to issue certificate: %v", err)
-		} else {
-			errMsg += "; rollbacked to issue certificate"
+		return fmt.Errorf("invalid certificate authority ARN: %s: %w", s, err)
+	}
+	if a.Service != "acm-pca" || !strings.HasPrefix(a.Resource, "certificate-authority/") {
+		return fmt.Errorf("invalid certificate authority ARN: %s", s)
+	}
+	return nil
+}
+
+// maxIdempotentRequests is the number of requests with derived idempotency tokens
+// until one returns a certificate that has not been deleted.
+const maxIdempotentRequests = 3
+
+// Request a certificate with the idempotency token of the options, and return its ARN and whether
+// ACM returned a certificate requested before with the same token, that is created before the request.
+// ACM returns the certificate requested with the same token within one hour even if it has been deleted,
+// such as by the rollback of a failed request. Then a derived token is changed with the deleted ARN,
+// so that retries of the same request still share a token, and an explicit token is an error.
+func requestIdempotentCertificate(ctx context.Context, api ACMAPI, o *IssueCertificateOptions, derived bool, request func(o IssueCertificateOptions) (string, error)) (string, bool, error) {
+	requestedAt := time.Now()
+	for i := 1; ; i++ {
+		arn, err := request(*o)
+		if err != nil {
+			return "", false, err
 		}
-		return IssueCertificateResult{}, errors.New(errMsg)
+
+		in := acm.DescribeCertificateInput{CertificateArn: aws.String(arn)}
+		out, err := api.DescribeCertificate(ctx, &in)
+		var nfe *types.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			if !derived || i >= maxIdempotentRequests {
+				return "", false, fmt.Errorf("the certificate requested with the idempotency token %s has been deleted, use another token: %s", o.IdempotencyToken, arn)
+			}
+			o.IdempotencyToken = retryIdempotencyToken(o.IdempotencyToken, arn)
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+
+		createdAt := out.Certificate.CreatedAt
+		return arn, createdAt != nil && createdAt.Before(requestedAt), nil
 	}
+}
 
-	if hzID == "" {
-		errMsg := "Cannot get public hosted zone ID"
-		if err := RollbackIssueCertificate(ctx, aAPI, rAPI, *c.Certificate.CertificateArn); err != nil {
-			errMsg += fmt.Sprintf("; Failed to rollback to issue certificate: %v", err)
-		} else {
-			errMsg += "; rollbacked to issue certificate"
+// Returns a token derived from the token that returned the deleted certificate.
+func retryIdempotencyToken(token, deletedArn string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", token, deletedArn)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// Request a certificate and return its ARN.
+func requestCertificate(ctx context.Context, api ACMRequestCertificateAPI, method, targetDomain, hostedDomain string, o IssueCertificateOptions) (string, error) {
+	reqIn := acm.RequestCertificateInput{
+		DomainName:       aws.String(targetDomain),
+		ValidationMethod: acmTypes.ValidationMethod(method),
+	}
+	for _, d := range append([]string{targetDomain}, o.SubjectAlternativeNames...) {
+		reqIn.DomainValidationOptions = append(reqIn.DomainValidationOptions, acmTypes.DomainValidationOption{
+			DomainName:       aws.String(d),
+			ValidationDomain: aws.String(hostedDomain),
+		})
+	}
+
+	return sendRequestCertificate(ctx, api, &reqIn, o)
+}
+
+// Request a certificate with the input completed by the options, and return its ARN.
+func sendRequestCertificate(ctx context.Context, api ACMRequestCertificateAPI, reqIn *acm.RequestCertificateInput, o IssueCertificateOptions) (string, error) {
+	targetDomain := aws.ToString(reqIn.DomainName)
+	if len(o.SubjectAlternativeNames) > 0 {
+		reqIn.SubjectAlternativeNames = append([]string{targetDomain}, o.SubjectAlternativeNames...)
+	}
+	if o.IdempotencyToken != "" {
+		reqIn.IdempotencyToken = aws.String(o.IdempotencyToken)
+	}
+	if o.KeyAlgorithm != "" {
+		reqIn.KeyAlgorithm = acmTypes.KeyAlgorithm(o.KeyAlgorithm)
+	}
+	if o.TransparencyLogging != "" {
+		reqIn.Options = &acmTypes.CertificateOptions{
+			CertificateTransparencyLoggingPreference: acmTypes.CertificateTransparencyLoggingPreference(o.TransparencyLogging),
 		}
-		return IssueCertificateResult{}, errors.New(errMsg)
+	}
+	if len(o.Tags) > 0 {
+		reqIn.Tags = toACMTags(o.Tags)
 	}
 
-	result.HosteZoneID = hzID
+	r, err := api.RequestCertificate(ctx, reqIn)
+	if err != nil {
+		return "", err
+	}
 
-	crsIn := route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: aws.String(hzID),
-		ChangeBatch: &route53Types.ChangeBatch{
-			Changes: []route53Types.Change{
-				{
-					Action: route53Types.ChangeActionCreate,
-					ResourceRecordSet: &route53Types.ResourceRecordSet{
-						Name: vRecordName,
-						Type: route53Types.RRTypeCname,
-						TTL:  aws.Int64(300),
-						ResourceRecords: []route53Types.ResourceRecord{
-							{
-								Value: vRecordValue,
-							},
-						},
-					},
-				},
+	return aws.ToString(r.CertificateArn), nil
+}
+
+// Return the distinct records that validate the domains of the certificate.
+// ACM needs a few seconds to generate the records after requesting the certificate.
+func describeValidationRecords(ctx context.Context, api ACMDescribeCertificateAPI, arn string) ([]RecordSet, error) {
+	dcIn := acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+
+	for i := 0; i < validationRecordRetries; i++ {
+		if err := sleepContext(ctx, validationRecordInterval); err != nil {
+			return nil, err
+		}
+
+		c, err := api.DescribeCertificate(ctx, &dcIn)
+		if err != nil {
+			return nil, err
+		}
+		if c.Certificate.DomainValidationOptions == nil {
+			return nil, errors.New("DomainValidationOptions dose not exists")
+		}
+
+		generated := true
+		for _, dv := range c.Certificate.DomainValidationOptions {
+			if dv.ResourceRecord == nil {
+				generated = false
+			}
+		}
+		if generated {
+			return validationRecordSets(c.Certificate), nil
+		}
+	}
+
+	return nil, errors.New("ResourceRecord of DomainValidationOptions dose not exists")
+}
+
+// Create a CNAME record that validates the domain, and return whether it is created.
+// The record is shared by certificates of the same domain in all regions,
+// so it is not created if the same record already exists.
+func createValidationRecord(ctx context.Context, rAPI Route53API, hzID, name, value string) (bool, error) {
+	exists, err := validationRecordExists(ctx, rAPI, hzID, name, value)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if err := changeValidationRecord(ctx, rAPI, hzID, route53Types.ChangeActionCreate, name, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Change the CNAME record that validates the domain with the action.
+func changeValidationRecord(ctx context.Context, rAPI Route53ChangeResourceRecordSetsAPI, hzID string, action route53Types.ChangeAction, name, value string) error {
+	return changeRecordSet(ctx, rAPI, hzID, action, route53Types.ResourceRecordSet{
+		Name: aws.String(name),
+		Type: route53Types.RRTypeCname,
+		TTL:  aws.Int64(300),
+		ResourceRecords: []route53Types.ResourceRecord{
+			{
+				Value: aws.String(value),
 			},
 		},
-	}
+	})
+}
 
-	_, err = rAPI.ChangeResourceRecordSets(ctx, &crsIn)
+// Return whether the CNAME record with the value exists in the hosted zone.
+func validationRecordExists(ctx context.Context, rAPI Route53ListResourceRecordSetsAPI, hzID, name, value string) (bool, error) {
+	in := route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hzID),
+		StartRecordName: aws.String(name),
+		StartRecordType: route53Types.RRTypeCname,
+		MaxItems:        aws.Int32(1),
+	}
+	out, err := rAPI.ListResourceRecordSets(ctx, &in)
 	if err != nil {
-		errMsg := err.Error()
-		if err := RollbackIssueCertificate(ctx, aAPI, rAPI, *c.Certificate.CertificateArn); err != nil {
-			errMsg += fmt.Sprintf("; Failed to rollback to issue certificate: %v", err)
-		} else {
-			errMsg += "; rollbacked to issue certificate"
+		return false, err
+	}
+
+	for _, rrs := range out.ResourceRecordSets {
+		if aws.ToString(rrs.Name) != name || rrs.Type != route53Types.RRTypeCname {
+			continue
+		}
+		for _, rr := range rrs.ResourceRecords {
+			if aws.ToString(rr.Value) == value {
+				return true, nil
+			}
 		}
-		return IssueCertificateResult{}, errors.New(errMsg)
 	}
+	return false, nil
+}
 
-	return result, nil
+// Rollback to issue the certificate, and return an error with the result of the rollback.
+// The certificate is kept if it existed before the request, because it may be in use.
+func rollbackError(ctx context.Context, aAPI ACMAPI, rAPI Route53API, result IssueCertificateResult, errMsg string) error {
+	if result.AlreadyExisted {
+		return errors.New(errMsg + "; kept the certificate requested before with the same idempotency token")
+	}
+	if err := RollbackIssueCertificate(ctx, aAPI, rAPI, result.CertificateArn); err != nil {
+		errMsg += fmt.Sprintf("; Failed to rollback to issue certificate: %v", err)
+	} else {
+		errMsg += "; rollbacked to issue certificate"
+	}
+	return errors.New(errMsg)
+}
+
+// WaitCertificateIssued waits until the status of the certificate becomes ISSUED, and returns the certificate.
+// If the certificate reaches a status that will never become ISSUED, it returns *CertificateStatusError.
+func WaitCertificateIssued(ctx context.Context, api ACMDescribeCertificateAPI, arn string, optFns ...func(*WaitCertificateOptions)) (Certificate, error) {
+	o := WaitCertificateOptions{
+		Interval: defaultWaitInterval,
+		Timeout:  defaultWaitTimeout,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	for {
+		c, err := GetCertificate(ctx, api, arn)
+		if err != nil {
+			return Certificate{}, err
+		}
+
+		switch acmTypes.CertificateStatus(c.Status) {
+		case acmTypes.CertificateStatusIssued:
+			return c, nil
+		case acmTypes.CertificateStatusPendingValidation:
+		default:
+			return c, &CertificateStatusError{Arn: arn, Status: c.Status, FailureReason: c.FailureReason}
+		}
+
+		if err := sleepContext(ctx, o.Interval); err != nil {
+			return c, err
+		}
+	}
+}
+
+// ImportCertificate imports a certificate, and returns the ARN of the imported certificate.
+func ImportCertificate(ctx context.Context, api ACMImportCertificateAPI, certificate, privateKey, certificateChain []byte) (string, error) {
+	in := acm.ImportCertificateInput{
+		Certificate:      certificate,
+		PrivateKey:       privateKey,
+		CertificateChain: certificateChain,
+	}
+	out, err := api.ImportCertificate(ctx, &in)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(out.CertificateArn), nil
+}
+
+// ExportCertificate exports a private certificate, the certificate chain and the private key
+// that is encrypted with the passphrase.
+func ExportCertificate(ctx context.Context, api ACMExportCertificateAPI, arn string, passphrase []byte) (ExportedCertificate, error) {
+	in := acm.ExportCertificateInput{
+		CertificateArn: aws.String(arn),
+		Passphrase:     passphrase,
+	}
+	out, err := api.ExportCertificate(ctx, &in)
+	if err != nil {
+		return ExportedCertificate{}, err
+	}
+
+	return ExportedCertificate{
+		Certificate:      aws.ToString(out.Certificate),
+		CertificateChain: aws.ToString(out.CertificateChain),
+		PrivateKey:       aws.ToString(out.PrivateKey),
+	}, nil
+}
+
+// Sleep for d, or return the error of the context if it is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
 
 // RollbackIssueCertificate rollbacks to issue an SSL certificate.
@@ -246,13 +1057,45 @@ func RollbackIssueCertificate(ctx context.Context, aAPI ACMAPI, rAPI Route53API,
 
 // DeleteRoute53RecordSet deletes a Route 53 record set.
 func DeleteRoute53RecordSet(ctx context.Context, aAPI ACMAPI, rAPI Route53API, rs RecordSet) error {
+	_, err := deleteRoute53RecordSet(ctx, rAPI, rs)
+	return err
+}
+
+// errRecordSetNotFound is an error that represents the record set to delete does not exist.
+var errRecordSetNotFound = errors.New("Target RecordeSet does not exists")
+
+// deletedRecordSet is a structure that represents a record deleted by deleteRoute53RecordSet, to restore it.
+type deletedRecordSet struct {
+	recordSet         RecordSet
+	hostedZoneID      string
+	resourceRecordSet route53Types.ResourceRecordSet
+}
+
+// Delete the record set, and return the deleted record.
+// hostedZoneID of the returned record is set if the hosted zone is found even if it fails.
+func deleteRoute53RecordSet(ctx context.Context, rAPI Route53API, rs RecordSet) (deletedRecordSet, error) {
+	d, err := findRoute53RecordSet(ctx, rAPI, rs)
+	if err != nil {
+		return d, err
+	}
+	if err := changeRecordSet(ctx, rAPI, d.hostedZoneID, route53Types.ChangeActionDelete, d.resourceRecordSet); err != nil {
+		return d, err
+	}
+
+	return d, nil
+}
+
+// Returns the record set to delete with the hosted zone and the TTL, or errRecordSetNotFound if it does not exist.
+func findRoute53RecordSet(ctx context.Context, rAPI Route53API, rs RecordSet) (deletedRecordSet, error) {
+	d := deletedRecordSet{recordSet: rs}
 	hzID, err := getPublicHostedZoneIDByDomainName(ctx, rAPI, rs.HostedDomainName)
 	if err != nil {
-		return err
+		return d, err
 	}
 	if hzID == "" {
-		return errors.New("Cannot get hosted zone ID")
+		return d, fmt.Errorf("Cannot get hosted zone ID of %s in %s", rs.HostedDomainName, apiLabel(rAPI, ServiceRoute53))
 	}
+	d.hostedZoneID = hzID
 
 	lrrsIn := route53.ListResourceRecordSetsInput{
 		HostedZoneId:    aws.String(hzID),
@@ -261,46 +1104,47 @@ func DeleteRoute53RecordSet(ctx context.Context, aAPI ACMAPI, rAPI Route53API, r
 	}
 	r, err := rAPI.ListResourceRecordSets(ctx, &lrrsIn)
 	if err != nil {
-		return err
+		return d, err
 	}
 
 	if len(r.ResourceRecordSets) != 1 {
-		return fmt.Errorf("Target RecordeSet does not exists: %s", rs.Name)
+		return d, fmt.Errorf("%w in %s: %s", errRecordSetNotFound, apiLabel(rAPI, ServiceRoute53), rs.Name)
 	}
 
 	rrs := r.ResourceRecordSets[0]
 	if aws.ToString(rrs.Name) != rs.Name {
-		return fmt.Errorf("Target RecordeSet does not exists: %s", rs.Name)
+		return d, fmt.Errorf("%w in %s: %s", errRecordSetNotFound, apiLabel(rAPI, ServiceRoute53), rs.Name)
+	}
+
+	d.resourceRecordSet = route53Types.ResourceRecordSet{
+		Name: aws.String(rs.Name),
+		Type: route53Types.RRType(rs.Type),
+		TTL:  rrs.TTL,
+		ResourceRecords: []route53Types.ResourceRecord{
+			{
+				Value: aws.String(rs.Value),
+			},
+		},
 	}
+	return d, nil
+}
 
+// Change the record set in the hosted zone with the action.
+func changeRecordSet(ctx context.Context, rAPI Route53ChangeResourceRecordSetsAPI, hzID string, action route53Types.ChangeAction, rrs route53Types.ResourceRecordSet) error {
 	crsIn := route53.ChangeResourceRecordSetsInput{
 		HostedZoneId: aws.String(hzID),
 		ChangeBatch: &route53Types.ChangeBatch{
 			Changes: []route53Types.Change{
 				{
-					Action: route53Types.ChangeActionDelete,
-					ResourceRecordSet: &route53Types.ResourceRecordSet{
-						Name: aws.String(rs.Name),
-						Type: route53Types.RRType(rs.Type),
-						TTL:  rrs.TTL,
-						ResourceRecords: []route53Types.ResourceRecord{
-							{
-								Value: aws.String(rs.Value),
-							},
-						},
-					},
+					Action:            action,
+					ResourceRecordSet: &rrs,
 				},
 			},
 		},
 	}
 
-	_, err = rAPI.ChangeResourceRecordSets(ctx, &crsIn)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	_, err := rAPI.ChangeResourceRecordSets(ctx, &crsIn)
+	return err
 }
 
 // Get public hosted zone ID by domain name.
@@ -322,3 +1166,29 @@ func getPublicHostedZoneIDByDomainName(ctx context.Context, rAPI Route53API, dom
 
 	return "", nil
 }
+
+// FindPublicHostedDomainName returns the domain name of the most specific public hosted zone
+// that the domain belongs to, such as "example.com" for "*.www.example.com".
+// It returns "" if no public hosted zone matches.
+func FindPublicHostedDomainName(ctx context.Context, rAPI Route53ListHostedZonesAPI, domainName string) (string, error) {
+	dn := strings.TrimPrefix(domainName, "*.") + "."
+
+	lhzIn := route53.ListHostedZonesInput{}
+	out, err := rAPI.ListHostedZones(ctx, &lhzIn)
+	if err != nil {
+		return "", err
+	}
+
+	found := ""
+	for _, hz := range out.HostedZones {
+		if hz.Config == nil || hz.Config.PrivateZone {
+			continue
+		}
+		name := aws.ToString(hz.Name)
+		if (dn == name || strings.HasSuffix(dn, "."+name)) && len(name) > len(found) {
+			found = name
+		}
+	}
+
+	return strings.TrimSuffix(found, "."), nil
+}
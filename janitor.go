@@ -0,0 +1,195 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// DefaultStalePendingAge is the age after which certificates pending validation are stale by default.
+const DefaultStalePendingAge = 24 * time.Hour
+
+// StaleCertificate is a structure that represents a certificate that CleanStalePendingCertificates found.
+// DeletedRecords are the records deleted with the certificate, and KeptRecords are the records
+// kept because other certificates use them.
+type StaleCertificate struct {
+	Region         string      `json:"region" yaml:"region"`
+	CertificateArn string      `json:"certificateArn" yaml:"certificateArn"`
+	DomainName     string      `json:"domainName" yaml:"domainName"`
+	Status         string      `json:"status" yaml:"status"`
+	CreatedAt      *time.Time  `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+	Deleted        bool        `json:"deleted" yaml:"deleted"`
+	DeletedRecords []RecordSet `json:"deletedRecords,omitempty" yaml:"deletedRecords,omitempty"`
+	KeptRecords    []RecordSet `json:"keptRecords,omitempty" yaml:"keptRecords,omitempty"`
+	Error          string      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// JanitorOptions is a structure that represents options for CleanStalePendingCertificates.
+type JanitorOptions struct {
+	// PendingFor is the age after which certificates pending validation are stale. Default is DefaultStalePendingAge.
+	PendingFor time.Duration
+
+	// Now is the time to compare with the creation time of certificates. Default is the current time.
+	Now time.Time
+
+	// DryRun finds the stale certificates without deleting them.
+	DryRun bool
+
+	// IncludeTimedOut also deletes the certificates that have timed out in validation for longer than PendingFor.
+	// Default is only the certificates pending validation, so that timed out certificates are kept for investigation.
+	IncludeTimedOut bool
+}
+
+// CleanStalePendingCertificates deletes the certificates in the regions that have been PENDING_VALIDATION
+// for longer than PendingFor, such as ones left by failed pipelines, with the records that validate the domains.
+// Certificates that have timed out in validation are deleted as well only if IncludeTimedOut is set. A record is the same for the certificates of the domain in all regions,
+// so records that other certificates in the regions use are kept. Nothing is deleted if listing certificates fails
+// in any region, because the records used there are unknown.
+// It returns the stale certificates, and an error that joins the errors of the certificates.
+func CleanStalePendingCertificates(ctx context.Context, apis RegionalACMAPI, rAPI Route53API, optFns ...func(*JanitorOptions)) ([]StaleCertificate, error) {
+	o := JanitorOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if o.PendingFor <= 0 {
+		o.PendingFor = DefaultStalePendingAge
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+
+	regions := make([]string, 0, len(apis))
+	for r := range apis {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+
+	stale := []StaleCertificate{}
+	staleRecords := [][]RecordSet{}
+	used := map[RecordSet]bool{}
+	for _, r := range regions {
+		summaries, err := ListCertificateSummaries(ctx, apis[r])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r, err)
+		}
+
+		for _, s := range summaries {
+			in := acm.DescribeCertificateInput{CertificateArn: s.CertificateArn}
+			out, err := apis[r].DescribeCertificate(ctx, &in)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r, err)
+			}
+
+			d := out.Certificate
+			rsList := validationRecordSets(d)
+			if !isStalePending(d, o.Now, o.PendingFor, o.IncludeTimedOut) {
+				for _, rs := range rsList {
+					used[rs] = true
+				}
+				continue
+			}
+
+			stale = append(stale, StaleCertificate{
+				Region:         r,
+				CertificateArn: aws.ToString(s.CertificateArn),
+				DomainName:     aws.ToString(d.DomainName),
+				Status:         string(d.Status),
+				CreatedAt:      d.CreatedAt,
+			})
+			staleRecords = append(staleRecords, rsList)
+		}
+	}
+
+	if o.DryRun {
+		for i := range stale {
+			for _, rs := range staleRecords[i] {
+				if used[rs] {
+					stale[i].KeptRecords = append(stale[i].KeptRecords, rs)
+				}
+			}
+		}
+		return stale, nil
+	}
+
+	// Delete the certificates first, so that records of certificates that fail to be deleted are kept.
+	msgs := []string{}
+	for i := range stale {
+		sc := &stale[i]
+		if err := deleteACMCertificate(ctx, apis[sc.Region], sc.CertificateArn); err != nil {
+			sc.Error = err.Error()
+			msgs = append(msgs, fmt.Sprintf("%s: %v", sc.CertificateArn, err))
+			for _, rs := range staleRecords[i] {
+				used[rs] = true
+			}
+			continue
+		}
+		sc.Deleted = true
+	}
+
+	deleted := map[RecordSet]bool{}
+	for i := range stale {
+		sc := &stale[i]
+		if !sc.Deleted {
+			continue
+		}
+
+		for _, rs := range staleRecords[i] {
+			if used[rs] {
+				sc.KeptRecords = append(sc.KeptRecords, rs)
+				continue
+			}
+			if deleted[rs] {
+				continue
+			}
+
+			_, err := deleteRoute53RecordSet(ctx, rAPI, rs)
+			if errors.Is(err, errRecordSetNotFound) {
+				// the record was never created or has been deleted
+				continue
+			}
+			if err != nil {
+				sc.Error = err.Error()
+				msgs = append(msgs, fmt.Sprintf("%s: %v", sc.CertificateArn, err))
+				continue
+			}
+			deleted[rs] = true
+			sc.DeletedRecords = append(sc.DeletedRecords, rs)
+		}
+	}
+
+	if len(msgs) > 0 {
+		return stale, errors.New(strings.Join(msgs, "; "))
+	}
+
+	return stale, nil
+}
+
+// CleanStalePendingCertificates deletes the stale certificates pending validation in the region
+// with the ACM and Route 53 clients.
+func (g *GoACM) CleanStalePendingCertificates(ctx context.Context, optFns ...func(*JanitorOptions)) ([]StaleCertificate, error) {
+	return CleanStalePendingCertificates(ctx, RegionalACMAPI{g.Region: g.ACMAPI()}, g.Route53API(), optFns...)
+}
+
+// CleanStalePendingCertificates deletes the stale certificates pending validation in all regions.
+func (m *MultiRegionGoACM) CleanStalePendingCertificates(ctx context.Context, optFns ...func(*JanitorOptions)) ([]StaleCertificate, error) {
+	return CleanStalePendingCertificates(ctx, m.ACMAPIs(), m.Route53API(), optFns...)
+}
+
+// Returns true if the certificate has been pending validation, or has timed out in validation if includeTimedOut is true,
+// for longer than the age.
+func isStalePending(d *types.CertificateDetail, now time.Time, age time.Duration, includeTimedOut bool) bool {
+	switch {
+	case d.Status == types.CertificateStatusPendingValidation:
+	case d.Status == types.CertificateStatusValidationTimedOut && includeTimedOut:
+	default:
+		return false
+	}
+	return d.CreatedAt != nil && now.Sub(*d.CreatedAt) >= age
+}
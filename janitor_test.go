@@ -0,0 +1,187 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CleanStalePendingCertificates(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	shared := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.shared.example.com",
+		Value:            "_validation.value.shared.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	orphan := goacm.RecordSet{
+		HostedDomainName: "example.net",
+		Name:             "_validation.name.orphan.example.net",
+		Value:            "_validation.value.orphan.example.net",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	fresh := goacm.RecordSet{
+		HostedDomainName: "example.org",
+		Name:             "_validation.name.fresh.example.org",
+		Value:            "_validation.value.fresh.example.org",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	certificate := func(name, status string, rs goacm.RecordSet, age time.Duration) goacm.MockACMParams {
+		return goacm.MockACMParams{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:ap-northeast-1:000000000000:certificate/" + name,
+				DomainName:          name + "." + rs.HostedDomainName,
+				Status:              status,
+				Type:                string(types.CertificateTypeAmazonIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rs,
+				CreatedAt:           aws.Time(now.Add(-age)),
+			},
+		}
+	}
+	tokyo := []goacm.MockACMParams{
+		certificate("shared", string(types.CertificateStatusPendingValidation), shared, 48*time.Hour),
+		certificate("orphan", string(types.CertificateStatusValidationTimedOut), orphan, 96*time.Hour),
+		certificate("fresh", string(types.CertificateStatusPendingValidation), fresh, time.Hour),
+	}
+	// the issued certificate of the same domain in another region uses the record
+	virginia := []goacm.MockACMParams{
+		certificate("shared", string(types.CertificateStatusIssued), shared, 48*time.Hour),
+	}
+	virginia[0].Certificate.Arn = "arn:aws:acm:us-east-1:000000000000:certificate/shared"
+
+	staleShared := goacm.StaleCertificate{
+		Region:         "ap-northeast-1",
+		CertificateArn: tokyo[0].Certificate.Arn,
+		DomainName:     tokyo[0].Certificate.DomainName,
+		Status:         tokyo[0].Certificate.Status,
+		CreatedAt:      tokyo[0].Certificate.CreatedAt,
+		KeptRecords:    []goacm.RecordSet{shared},
+	}
+	staleOrphan := goacm.StaleCertificate{
+		Region:         "ap-northeast-1",
+		CertificateArn: tokyo[1].Certificate.Arn,
+		DomainName:     tokyo[1].Certificate.DomainName,
+		Status:         tokyo[1].Certificate.Status,
+		CreatedAt:      tokyo[1].Certificate.CreatedAt,
+	}
+	deleted := func(sc goacm.StaleCertificate, rsList ...goacm.RecordSet) goacm.StaleCertificate {
+		sc.Deleted = true
+		sc.DeletedRecords = rsList
+		return sc
+	}
+
+	rp := []goacm.MockRoute53Params{
+		{RecordSet: shared, ChangeAction: route53Types.ChangeActionDelete},
+		{RecordSet: orphan, ChangeAction: route53Types.ChangeActionDelete},
+	}
+
+	cases := []struct {
+		name                 string
+		route53Params        []goacm.MockRoute53Params
+		includeTimedOut      bool
+		dryRun               bool
+		listErr              error
+		wantErr              bool
+		expect               []goacm.StaleCertificate
+		expectDeleted        []string
+		expectDeletedRecords []string
+	}{
+		{
+			name:                 "normal",
+			route53Params:        rp,
+			includeTimedOut:      true,
+			expect:               []goacm.StaleCertificate{deleted(staleShared), deleted(staleOrphan, orphan)},
+			expectDeleted:        []string{tokyo[0].Certificate.Arn, tokyo[1].Certificate.Arn},
+			expectDeletedRecords: []string{orphan.Name},
+		},
+		{
+			name:                 "normal: dry run",
+			route53Params:        rp,
+			includeTimedOut:      true,
+			dryRun:               true,
+			expect:               []goacm.StaleCertificate{staleShared, staleOrphan},
+			expectDeleted:        []string{},
+			expectDeletedRecords: []string{},
+		},
+		{
+			name: "normal: record already deleted",
+			route53Params: []goacm.MockRoute53Params{
+				{RecordSet: goacm.RecordSet{HostedDomainName: "example.net"}},
+			},
+			includeTimedOut:      true,
+			expect:               []goacm.StaleCertificate{deleted(staleShared), deleted(staleOrphan)},
+			expectDeleted:        []string{tokyo[0].Certificate.Arn, tokyo[1].Certificate.Arn},
+			expectDeletedRecords: []string{},
+		},
+		{
+			name:                 "normal: timed out certificates are kept",
+			route53Params:        rp,
+			expect:               []goacm.StaleCertificate{deleted(staleShared)},
+			expectDeleted:        []string{tokyo[0].Certificate.Arn},
+			expectDeletedRecords: []string{},
+		},
+		{
+			name:                 "error: failed to list in a region",
+			route53Params:        rp,
+			listErr:              errors.New("throttled"),
+			wantErr:              true,
+			expectDeleted:        []string{},
+			expectDeletedRecords: []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			deletedArns := []string{}
+			trackDelete := func(api goacm.MockACMAPI) goacm.MockACMAPI {
+				del := api.DeleteCertificateAPI
+				api.DeleteCertificateAPI = func(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+					deletedArns = append(deletedArns, aws.ToString(params.CertificateArn))
+					return del(ctx, params, optFns...)
+				}
+				return api
+			}
+			tokyoAPI := trackDelete(goacm.NewMockACMAPI(tokyo))
+			virginiaAPI := trackDelete(goacm.NewMockACMAPI(virginia))
+			if c.listErr != nil {
+				virginiaAPI.ListCertificatesAPI = func(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+					return nil, c.listErr
+				}
+			}
+
+			rAPI := goacm.NewMockRoute53API(c.route53Params)
+			deletedRecords := []string{}
+			change := rAPI.ChangeResourceRecordSetsAPI
+			rAPI.ChangeResourceRecordSetsAPI = func(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+				deletedRecords = append(deletedRecords, aws.ToString(params.ChangeBatch.Changes[0].ResourceRecordSet.Name))
+				return change(ctx, params, optFns...)
+			}
+
+			apis := goacm.RegionalACMAPI{"ap-northeast-1": tokyoAPI, "us-east-1": virginiaAPI}
+			stale, err := goacm.CleanStalePendingCertificates(context.TODO(), apis, rAPI, func(o *goacm.JanitorOptions) {
+				o.Now = now
+				o.IncludeTimedOut = c.includeTimedOut
+				o.DryRun = c.dryRun
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.Equal(tt, c.expect, stale)
+			assert.Equal(tt, c.expectDeleted, deletedArns)
+			assert.Equal(tt, c.expectDeletedRecords, deletedRecords)
+		})
+	}
+}
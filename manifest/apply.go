@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+)
+
+// Apply makes the certificates in the regions match the manifest with goacm.Reconcile.
+// Hosted zones that are not set are resolved to the most specific public hosted zone of the domain name,
+// and subject alternative names validated by DNS must resolve to the same zone.
+// The owner of the manifest is used as ReconcileOptions.Owner, and can be overridden by optFns.
+// apis must have clients of all regions returned by Regions.
+func Apply(ctx context.Context, m *Manifest, apis goacm.RegionalACMAPI, rAPI goacm.Route53API, optFns ...func(*goacm.ReconcileOptions)) (goacm.ReconcileResult, error) {
+	specs, err := m.resolveSpecs(ctx, rAPI)
+	if err != nil {
+		return goacm.ReconcileResult{}, err
+	}
+
+	fns := optFns
+	if m.Owner != "" {
+		fns = append([]func(*goacm.ReconcileOptions){func(o *goacm.ReconcileOptions) {
+			o.Owner = m.Owner
+		}}, optFns...)
+	}
+
+	return goacm.Reconcile(ctx, apis, rAPI, specs, fns...)
+}
+
+// Returns the specs with the resolved hosted zones. The records of all domains of a certificate are created
+// in the zone of the domain name, so subject alternative names in other zones are errors.
+func (m *Manifest) resolveSpecs(ctx context.Context, rAPI goacm.Route53ListHostedZonesAPI) ([]goacm.CertificateSpec, error) {
+	specs := m.Specs()
+	errs := Errors{}
+	for i, c := range m.Certificates {
+		if specs[i].HostedDomainName != "" {
+			continue
+		}
+
+		hd, err := goacm.FindPublicHostedDomainName(ctx, rAPI, c.DomainName)
+		if err != nil {
+			return nil, err
+		}
+		if hd == "" {
+			errs = append(errs, &Error{
+				File: m.File,
+				Line: c.line,
+				Msg:  fmt.Sprintf("certificates[%d]: public hosted zone not found for %s", i, c.DomainName),
+			})
+			continue
+		}
+		specs[i].HostedDomainName = hd
+
+		if specs[i].ValidationMethod == string(acmTypes.ValidationMethodEmail) {
+			continue
+		}
+		for j, san := range c.SubjectAlternativeNames {
+			shd, err := goacm.FindPublicHostedDomainName(ctx, rAPI, san)
+			if err != nil {
+				return nil, err
+			}
+			if shd == hd {
+				continue
+			}
+
+			msg := fmt.Sprintf("certificates[%d].subjectAlternativeNames[%d]: %s belongs to the public hosted zone %s, not %s of %s", i, j, san, shd, hd, c.DomainName)
+			if shd == "" {
+				msg = fmt.Sprintf("certificates[%d].subjectAlternativeNames[%d]: public hosted zone not found for %s", i, j, san)
+			}
+			errs = append(errs, &Error{
+				File: m.File,
+				Line: c.sanLines[j],
+				Msg:  msg,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return specs, nil
+}
@@ -0,0 +1,272 @@
+// Package manifest loads manifest files that describe certificates managed by goacm,
+// and applies them with goacm.Reconcile.
+//
+// A manifest is a YAML or JSON file such as:
+//
+//	version: 1
+//	owner: platform
+//	defaults:
+//	  hostedZone: example.com
+//	  regions: [us-east-1]
+//	  tags:
+//	    team: platform
+//	certificates:
+//	  - domainName: example.com
+//	    subjectAlternativeNames: ["*.example.com"]
+//	    regions: [us-east-1, ap-northeast-1]
+//	    tags:
+//	      env: prod
+//	  - domainName: internal.example.com
+//	    transparencyLogging: DISABLED
+//
+// Fields of defaults are used for certificates that do not set them. Tags are merged.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/michimani/goacm"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the manifest schema supported by this package.
+const Version = 1
+
+// Manifest is a structure that represents a manifest file.
+type Manifest struct {
+	Version int `json:"version" yaml:"version"`
+
+	// Owner is a value of the ownership tag of the certificates. Default is goacm.DefaultOwner.
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+
+	Defaults     Defaults      `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	Certificates []Certificate `json:"certificates" yaml:"certificates"`
+
+	// File is the name of the file used in errors.
+	File string `json:"-" yaml:"-"`
+}
+
+// Defaults is a structure that represents default fields of certificates.
+type Defaults struct {
+	HostedZone          string            `json:"hostedZone,omitempty" yaml:"hostedZone,omitempty"`
+	ValidationMethod    string            `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+	Regions             []string          `json:"regions,omitempty" yaml:"regions,omitempty"`
+	Tags                map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	KeyAlgorithm        string            `json:"keyAlgorithm,omitempty" yaml:"keyAlgorithm,omitempty"`
+	TransparencyLogging string            `json:"transparencyLogging,omitempty" yaml:"transparencyLogging,omitempty"`
+}
+
+// Certificate is a structure that represents a certificate in a manifest.
+type Certificate struct {
+	DomainName              string   `json:"domainName" yaml:"domainName"`
+	SubjectAlternativeNames []string `json:"subjectAlternativeNames,omitempty" yaml:"subjectAlternativeNames,omitempty"`
+
+	// HostedZone is the domain name of the public hosted zone that validates the domains.
+	// If it is empty, the most specific public hosted zone of the domain name is used.
+	HostedZone string `json:"hostedZone,omitempty" yaml:"hostedZone,omitempty"`
+
+	// ValidationMethod is DNS or EMAIL. Default is DNS.
+	ValidationMethod string `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+
+	Regions      []string          `json:"regions,omitempty" yaml:"regions,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	KeyAlgorithm string            `json:"keyAlgorithm,omitempty" yaml:"keyAlgorithm,omitempty"`
+
+	// TransparencyLogging is ENABLED or DISABLED. Default is ENABLED.
+	TransparencyLogging string `json:"transparencyLogging,omitempty" yaml:"transparencyLogging,omitempty"`
+
+	// line is the line of the certificate in the file, and sanLines are the lines of the subject alternative names.
+	line     int
+	sanLines []int
+}
+
+// Error is an error at a line of a manifest file. Line is 0 if unknown.
+type Error struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Errors is an error that represents all errors in a manifest file.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Load reads and parses the manifest file.
+func Load(file string) (*Manifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, file)
+}
+
+// Parse parses the manifest in YAML or JSON, and validates it strictly.
+// Unknown fields are errors. Errors have the line numbers in the file, and are returned as Errors.
+func Parse(data []byte, file string) (*Manifest, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, Errors{{File: file, Msg: strings.TrimPrefix(err.Error(), "yaml: ")}}
+	}
+	if len(doc.Content) == 0 {
+		return nil, Errors{{File: file, Msg: "manifest is empty"}}
+	}
+
+	m := Manifest{File: file}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, decodeErrors(file, err)
+	}
+
+	root := doc.Content[0]
+	for i := range m.Certificates {
+		m.Certificates[i].line = lineOf(root, "certificates", i)
+		for j := range m.Certificates[i].SubjectAlternativeNames {
+			m.Certificates[i].sanLines = append(m.Certificates[i].sanLines, lineOf(root, "certificates", i, "subjectAlternativeNames", j))
+		}
+	}
+
+	if errs := validate(&m, root); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &m, nil
+}
+
+// Specs returns the specs of the certificates with the defaults.
+// HostedDomainName of the specs is empty if the hosted zone is not set.
+func (m *Manifest) Specs() []goacm.CertificateSpec {
+	specs := make([]goacm.CertificateSpec, 0, len(m.Certificates))
+	for _, c := range m.Certificates {
+		c = m.withDefaults(c)
+		specs = append(specs, goacm.CertificateSpec{
+			DomainName:              c.DomainName,
+			SubjectAlternativeNames: c.SubjectAlternativeNames,
+			HostedDomainName:        c.HostedZone,
+			ValidationMethod:        c.ValidationMethod,
+			KeyAlgorithm:            c.KeyAlgorithm,
+			TransparencyLogging:     c.TransparencyLogging,
+			Tags:                    c.Tags,
+			Regions:                 c.Regions,
+		})
+	}
+	return specs
+}
+
+// Regions returns the sorted regions of all certificates.
+func (m *Manifest) Regions() []string {
+	seen := map[string]bool{}
+	regions := []string{}
+	for _, c := range m.Certificates {
+		for _, r := range m.withDefaults(c).Regions {
+			if !seen[r] {
+				seen[r] = true
+				regions = append(regions, r)
+			}
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Returns the certificate with the defaults.
+func (m *Manifest) withDefaults(c Certificate) Certificate {
+	d := m.Defaults
+	if c.HostedZone == "" {
+		c.HostedZone = d.HostedZone
+	}
+	if c.ValidationMethod == "" {
+		c.ValidationMethod = d.ValidationMethod
+	}
+	if len(c.Regions) == 0 {
+		c.Regions = d.Regions
+	}
+	if c.KeyAlgorithm == "" {
+		c.KeyAlgorithm = d.KeyAlgorithm
+	}
+	if c.TransparencyLogging == "" {
+		c.TransparencyLogging = d.TransparencyLogging
+	}
+
+	if len(d.Tags) > 0 {
+		tags := map[string]string{}
+		for k, v := range d.Tags {
+			tags[k] = v
+		}
+		for k, v := range c.Tags {
+			tags[k] = v
+		}
+		c.Tags = tags
+	}
+	return c
+}
+
+// Convert errors of decoding such as unknown fields into Errors with the line numbers.
+func decodeErrors(file string, err error) error {
+	te, ok := err.(*yaml.TypeError)
+	if !ok {
+		return Errors{{File: file, Msg: strings.TrimPrefix(err.Error(), "yaml: ")}}
+	}
+
+	errs := Errors{}
+	for _, msg := range te.Errors {
+		e := &Error{File: file, Msg: msg}
+		var rest string
+		if n, _ := fmt.Sscanf(msg, "line %d: ", &e.Line); n == 1 {
+			if i := strings.Index(msg, ": "); i >= 0 {
+				rest = msg[i+2:]
+			}
+			e.Msg = rest
+		}
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Returns the line of the node at the path of mapping keys and sequence indexes.
+// If the path does not exist, the line of the deepest existing node is returned.
+func lineOf(node *yaml.Node, path ...interface{}) int {
+	line := node.Line
+	for _, p := range path {
+		var next *yaml.Node
+		switch key := p.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				return line
+			}
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					next = node.Content[i+1]
+				}
+			}
+		case int:
+			if node.Kind != yaml.SequenceNode || key >= len(node.Content) {
+				return line
+			}
+			next = node.Content[key]
+		}
+		if next == nil {
+			return line
+		}
+		node, line = next, next.Line
+	}
+	return line
+}
@@ -0,0 +1,281 @@
+package manifest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/manifest"
+	"github.com/stretchr/testify/assert"
+)
+
+const validManifest = `version: 1
+owner: platform
+defaults:
+  hostedZone: example.com
+  regions: [us-east-1]
+  tags:
+    team: platform
+certificates:
+  - domainName: example.com
+    subjectAlternativeNames: ["*.example.com"]
+    regions: [us-east-1, ap-northeast-1]
+    tags:
+      env: prod
+  - domainName: api.example.com
+    keyAlgorithm: EC_prime256v1
+`
+
+func Test_Parse(t *testing.T) {
+	cases := []struct {
+		name   string
+		data   string
+		expect []string
+	}{
+		{
+			name: "normal: yaml",
+			data: validManifest,
+		},
+		{
+			name: "normal: json",
+			data: `{"version": 1, "certificates": [{"domainName": "example.com", "regions": ["us-east-1"]}]}`,
+		},
+		{
+			name:   "error: empty",
+			data:   "",
+			expect: []string{"certs.yaml: manifest is empty"},
+		},
+		{
+			name:   "error: syntax",
+			data:   "version: [1\n",
+			expect: []string{"certs.yaml: line 1: did not find expected ',' or ']'"},
+		},
+		{
+			name: "error: unknown field",
+			data: `version: 1
+certificates:
+  - domainName: example.com
+    region: [us-east-1]
+`,
+			expect: []string{"certs.yaml:4: field region not found in type manifest.Certificate"},
+		},
+		{
+			name: "error: unsupported version",
+			data: `version: 2
+certificates: []
+`,
+			expect: []string{"certs.yaml:1: version: unsupported version 2, supported version is 1"},
+		},
+		{
+			name: "error: invalid fields",
+			data: `version: 1
+defaults:
+  validationMethod: HTTP
+certificates:
+  - domainName: Example.com
+    hostedZone: example.com
+    regions: [us-east-1, us-east-1, tokyo]
+  - domainName: www.example.org
+    hostedZone: example.com
+    keyAlgorithm: RSA_4096
+    transparencyLogging: "OFF"
+    tags:
+      aws:name: test
+      goacm:owner: me
+  - subjectAlternativeNames: ["*.example.com"]
+`,
+			expect: []string{
+				"certs.yaml:3: defaults.validationMethod: invalid validation method \"HTTP\", expected DNS or EMAIL",
+				"certs.yaml:5: certificates[0].domainName: invalid domain name, use lower case letters, digits, hyphens and a leading \"*.\": Example.com",
+				"certs.yaml:7: certificates[0].regions[1]: duplicated region: us-east-1",
+				"certs.yaml:7: certificates[0].regions[2]: invalid region: tokyo",
+				"certs.yaml:5: certificates[0]: Example.com does not belong to the hosted zone example.com",
+				"certs.yaml:13: certificates[1].tags.aws:name: tag key must not start with \"aws:\": aws:name",
+				"certs.yaml:14: certificates[1].tags.goacm:owner: tag key goacm:owner is reserved for the owner",
+				"certs.yaml:10: certificates[1].keyAlgorithm: key algorithm is not supported in requesting certificates: RSA_4096 (supported: RSA_2048, EC_prime256v1, EC_secp384r1)",
+				"certs.yaml:11: certificates[1].transparencyLogging: invalid transparency logging preference: OFF (supported: ENABLED, DISABLED)",
+				"certs.yaml:8: certificates[1]: regions are required in the certificate or defaults",
+				"certs.yaml:8: certificates[1]: www.example.org does not belong to the hosted zone example.com",
+				"certs.yaml:15: certificates[2]: domainName is required",
+				"certs.yaml:15: certificates[2]: regions are required in the certificate or defaults",
+			},
+		},
+		{
+			name: "error: duplicated certificate",
+			data: `version: 1
+defaults:
+  regions: [us-east-1]
+certificates:
+  - domainName: example.com
+  - domainName: example.com
+    regions: [us-east-1]
+`,
+			expect: []string{"certs.yaml:6: certificates[1]: example.com in us-east-1 is duplicated with certificates[0]"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			m, err := manifest.Parse([]byte(c.data), "certs.yaml")
+			if len(c.expect) > 0 {
+				errs, ok := err.(manifest.Errors)
+				if !assert.True(tt, ok, "%v", err) {
+					return
+				}
+				msgs := make([]string, len(errs))
+				for i, e := range errs {
+					msgs[i] = e.Error()
+				}
+				assert.Equal(tt, c.expect, msgs)
+				assert.Nil(tt, m)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.NotNil(tt, m)
+		})
+	}
+}
+
+func Test_Specs(t *testing.T) {
+	m, err := manifest.Parse([]byte(validManifest), "certs.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expect := []goacm.CertificateSpec{
+		{
+			DomainName:              "example.com",
+			SubjectAlternativeNames: []string{"*.example.com"},
+			HostedDomainName:        "example.com",
+			Tags:                    map[string]string{"team": "platform", "env": "prod"},
+			Regions:                 []string{"us-east-1", "ap-northeast-1"},
+		},
+		{
+			DomainName:       "api.example.com",
+			HostedDomainName: "example.com",
+			KeyAlgorithm:     string(types.KeyAlgorithmEcPrime256v1),
+			Tags:             map[string]string{"team": "platform"},
+			Regions:          []string{"us-east-1"},
+		},
+	}
+
+	assert.Equal(t, expect, m.Specs())
+	assert.Equal(t, []string{"ap-northeast-1", "us-east-1"}, m.Regions())
+}
+
+func Test_Apply(t *testing.T) {
+	rs := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.www.example.com",
+		Value:            "_validation.value.www.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	certificate := goacm.MockACMParams{
+		Certificate: goacm.Certificate{
+			Arn:                 "arn:aws:acm:us-east-1:000000000000:certificate/www",
+			DomainName:          "www.example.com",
+			Status:              string(types.CertificateStatusIssued),
+			Type:                string(types.CertificateTypeAmazonIssued),
+			ValidationMethod:    string(types.ValidationMethodDns),
+			ValidationRecordSet: rs,
+		},
+		Tags: map[string]string{goacm.DefaultOwnerTagKey: "platform"},
+	}
+
+	cases := []struct {
+		name    string
+		data    string
+		wantErr string
+		expect  []goacm.ReconcileChange
+	}{
+		{
+			name: "normal: hosted zone is resolved",
+			data: `version: 1
+owner: platform
+certificates:
+  - domainName: www.example.com
+    regions: [us-east-1]
+    tags:
+      env: prod
+`,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionRetag,
+					Region:         "us-east-1",
+					DomainName:     "www.example.com",
+					CertificateArn: certificate.Certificate.Arn,
+					Tags:           map[string]string{"env": "prod"},
+				},
+			},
+		},
+		{
+			name: "error: hosted zone not found",
+			data: `version: 1
+certificates:
+  - domainName: www.example.org
+    regions: [us-east-1]
+`,
+			wantErr: "certs.yaml:3: certificates[0]: public hosted zone not found for www.example.org",
+		},
+		{
+			name: "error: subject alternative names in other hosted zones",
+			data: `version: 1
+certificates:
+  - domainName: www.example.com
+    subjectAlternativeNames:
+      - "*.example.com"
+      - www.example.net
+      - www.example.org
+    regions: [us-east-1]
+`,
+			wantErr: "certs.yaml:6: certificates[0].subjectAlternativeNames[1]: www.example.net belongs to the public hosted zone example.net, not example.com of www.example.com\n" +
+				"certs.yaml:7: certificates[0].subjectAlternativeNames[2]: public hosted zone not found for www.example.org",
+		},
+		{
+			name: "normal: transparency logging is disabled",
+			data: `version: 1
+owner: platform
+defaults:
+  hostedZone: example.com
+certificates:
+  - domainName: www.example.com
+    regions: [us-east-1]
+    transparencyLogging: DISABLED
+`,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:              goacm.ReconcileActionUpdate,
+					Region:              "us-east-1",
+					DomainName:          "www.example.com",
+					CertificateArn:      certificate.Certificate.Arn,
+					TransparencyLogging: "DISABLED",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			m, err := manifest.Parse([]byte(c.data), "certs.yaml")
+			if !assert.NoError(tt, err) {
+				return
+			}
+
+			apis := goacm.RegionalACMAPI{"us-east-1": goacm.NewMockACMAPI([]goacm.MockACMParams{certificate})}
+			rAPI := goacm.NewMockRoute53API([]goacm.MockRoute53Params{{RecordSet: rs}, {RecordSet: goacm.RecordSet{HostedDomainName: "example.net"}}})
+			res, err := manifest.Apply(context.TODO(), m, apis, rAPI, func(o *goacm.ReconcileOptions) {
+				o.DryRun = true
+			})
+			if c.wantErr != "" {
+				assert.EqualError(tt, err, c.wantErr)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, res.Changes)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	domainLabelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	regionPattern      = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]*)?-[a-z]+-[0-9]+$`)
+)
+
+// validator collects errors with the line numbers of the nodes.
+type validator struct {
+	file string
+	root *yaml.Node
+	errs Errors
+}
+
+func (v *validator) errorf(path []interface{}, format string, args ...interface{}) {
+	v.errs = append(v.errs, &Error{
+		File: v.file,
+		Line: lineOf(v.root, path...),
+		Msg:  fmt.Sprintf("%s: %s", pathString(path), fmt.Sprintf(format, args...)),
+	})
+}
+
+// Validate the manifest, and return all errors.
+func validate(m *Manifest, root *yaml.Node) Errors {
+	v := &validator{file: m.File, root: root}
+
+	if m.Version != Version {
+		v.errorf([]interface{}{"version"}, "unsupported version %d, supported version is %d", m.Version, Version)
+		// other fields may have different meanings in other versions
+		return v.errs
+	}
+
+	d := m.Defaults
+	dp := []interface{}{"defaults"}
+	v.hostedZone(append(dp, "hostedZone"), d.HostedZone)
+	v.validationMethod(append(dp, "validationMethod"), d.ValidationMethod)
+	v.regions(append(dp, "regions"), d.Regions)
+	v.tags(append(dp, "tags"), d.Tags)
+	v.keyAlgorithm(append(dp, "keyAlgorithm"), d.KeyAlgorithm)
+	v.transparencyLogging(append(dp, "transparencyLogging"), d.TransparencyLogging)
+
+	if len(m.Certificates) == 0 {
+		v.errorf([]interface{}{"certificates"}, "at least one certificate is required")
+	}
+
+	seen := map[string]int{}
+	for i, c := range m.Certificates {
+		p := []interface{}{"certificates", i}
+		at := func(key string) []interface{} {
+			return append(append([]interface{}{}, p...), key)
+		}
+
+		if c.DomainName == "" {
+			v.errorf(p, "domainName is required")
+		} else {
+			v.domainName(at("domainName"), c.DomainName)
+		}
+		for j, san := range c.SubjectAlternativeNames {
+			v.domainName(append(at("subjectAlternativeNames"), j), san)
+		}
+		v.hostedZone(at("hostedZone"), c.HostedZone)
+		v.validationMethod(at("validationMethod"), c.ValidationMethod)
+		v.regions(at("regions"), c.Regions)
+		v.tags(at("tags"), c.Tags)
+		v.keyAlgorithm(at("keyAlgorithm"), c.KeyAlgorithm)
+		v.transparencyLogging(at("transparencyLogging"), c.TransparencyLogging)
+
+		merged := m.withDefaults(c)
+		if len(merged.Regions) == 0 {
+			v.errorf(p, "regions are required in the certificate or defaults")
+		}
+		if merged.HostedZone != "" && merged.ValidationMethod != string(acmTypes.ValidationMethodEmail) {
+			for _, d := range append([]string{c.DomainName}, c.SubjectAlternativeNames...) {
+				if d != "" && !inZone(d, merged.HostedZone) {
+					v.errorf(p, "%s does not belong to the hosted zone %s", d, merged.HostedZone)
+				}
+			}
+		}
+
+		for _, r := range merged.Regions {
+			key := r + " " + c.DomainName
+			if j, ok := seen[key]; ok && j != i {
+				v.errorf(p, "%s in %s is duplicated with certificates[%d]", c.DomainName, r, j)
+				continue
+			}
+			seen[key] = i
+		}
+	}
+
+	return v.errs
+}
+
+func (v *validator) domainName(path []interface{}, name string) {
+	if len(name) > 253 {
+		v.errorf(path, "domain name is longer than 253 characters: %s", name)
+		return
+	}
+
+	labels := strings.Split(strings.TrimPrefix(name, "*."), ".")
+	if len(labels) < 2 {
+		v.errorf(path, "invalid domain name: %s", name)
+		return
+	}
+	for _, l := range labels {
+		if !domainLabelPattern.MatchString(l) {
+			v.errorf(path, "invalid domain name, use lower case letters, digits, hyphens and a leading \"*.\": %s", name)
+			return
+		}
+	}
+}
+
+func (v *validator) hostedZone(path []interface{}, zone string) {
+	if zone != "" {
+		v.domainName(path, zone)
+	}
+}
+
+func (v *validator) validationMethod(path []interface{}, method string) {
+	switch method {
+	case "", string(acmTypes.ValidationMethodDns), string(acmTypes.ValidationMethodEmail):
+	default:
+		v.errorf(path, "invalid validation method %q, expected DNS or EMAIL", method)
+	}
+}
+
+func (v *validator) regions(path []interface{}, regions []string) {
+	seen := map[string]bool{}
+	for i, r := range regions {
+		if !regionPattern.MatchString(r) {
+			v.errorf(append(path, i), "invalid region: %s", r)
+		}
+		if seen[r] {
+			v.errorf(append(path, i), "duplicated region: %s", r)
+		}
+		seen[r] = true
+	}
+}
+
+func (v *validator) tags(path []interface{}, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		val := tags[k]
+		switch {
+		case len(k) == 0 || len(k) > 128:
+			v.errorf(append(path, k), "tag key must have 1 to 128 characters: %q", k)
+		case strings.HasPrefix(strings.ToLower(k), "aws:"):
+			v.errorf(append(path, k), "tag key must not start with \"aws:\": %s", k)
+		case k == goacm.DefaultOwnerTagKey:
+			v.errorf(append(path, k), "tag key %s is reserved for the owner", k)
+		case len(val) > 256:
+			v.errorf(append(path, k), "tag value must have at most 256 characters: %s", k)
+		}
+	}
+}
+
+func (v *validator) keyAlgorithm(path []interface{}, algorithm string) {
+	if err := goacm.ValidateKeyAlgorithm(algorithm); err != nil {
+		v.errorf(path, "%v", err)
+	}
+}
+
+func (v *validator) transparencyLogging(path []interface{}, preference string) {
+	if err := goacm.ValidateTransparencyLogging(preference); err != nil {
+		v.errorf(path, "%v", err)
+	}
+}
+
+// Returns whether the domain belongs to the hosted zone.
+func inZone(domainName, zone string) bool {
+	d := strings.TrimPrefix(domainName, "*.")
+	return d == zone || strings.HasSuffix(d, "."+zone)
+}
+
+// Returns a path such as "certificates[1].regions[0]".
+func pathString(path []interface{}) string {
+	var b strings.Builder
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(key)
+		case int:
+			fmt.Fprintf(&b, "[%d]", key)
+		}
+	}
+	return b.String()
+}
@@ -8,7 +8,10 @@ import (
 
 // MockACMParams is a structure with the elements needed to generate a mock.
 type MockACMParams struct {
-	Certificate Certificate
+	Certificate         Certificate
+	Tags                map[string]string
+	ExportedCertificate ExportedCertificate
+	RenewalSummary      *RenewalSummary
 }
 
 // MockACMAPI is a struct that represents an ACM client.
@@ -17,6 +20,15 @@ type MockACMAPI struct {
 	DescribeCertificateAPI MockACMDescribeCertificateAPI
 	DeleteCertificateAPI   MockACMDeleteCertificateAPI
 	RequestCertificateAPI  MockACMRequestCertificateAPI
+	ImportCertificateAPI   MockACMImportCertificateAPI
+	ExportCertificateAPI   MockACMExportCertificateAPI
+
+	ListTagsForCertificateAPI    MockACMListTagsForCertificateAPI
+	AddTagsToCertificateAPI      MockACMAddTagsToCertificateAPI
+	RemoveTagsFromCertificateAPI MockACMRemoveTagsFromCertificateAPI
+	UpdateCertificateOptionsAPI  MockACMUpdateCertificateOptionsAPI
+	RenewCertificateAPI          MockACMRenewCertificateAPI
+	ResendValidationEmailAPI     MockACMResendValidationEmailAPI
 }
 
 // MockACMDescribeCertificateAPI is a type that represents a function that mock ACM's DescribeCertificate.
@@ -31,6 +43,30 @@ type MockACMDeleteCertificateAPI func(ctx context.Context, params *acm.DeleteCer
 // MockACMRequestCertificateAPI is a type that represents a function that mock ACM's RequestCertificate.
 type MockACMRequestCertificateAPI func(ctx context.Context, params *acm.RequestCertificateInput, optFns ...func(*acm.Options)) (*acm.RequestCertificateOutput, error)
 
+// MockACMImportCertificateAPI is a type that represents a function that mock ACM's ImportCertificate.
+type MockACMImportCertificateAPI func(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error)
+
+// MockACMExportCertificateAPI is a type that represents a function that mock ACM's ExportCertificate.
+type MockACMExportCertificateAPI func(ctx context.Context, params *acm.ExportCertificateInput, optFns ...func(*acm.Options)) (*acm.ExportCertificateOutput, error)
+
+// MockACMListTagsForCertificateAPI is a type that represents a function that mock ACM's ListTagsForCertificate.
+type MockACMListTagsForCertificateAPI func(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error)
+
+// MockACMAddTagsToCertificateAPI is a type that represents a function that mock ACM's AddTagsToCertificate.
+type MockACMAddTagsToCertificateAPI func(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error)
+
+// MockACMRemoveTagsFromCertificateAPI is a type that represents a function that mock ACM's RemoveTagsFromCertificate.
+type MockACMRemoveTagsFromCertificateAPI func(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error)
+
+// MockACMUpdateCertificateOptionsAPI is a type that represents a function that mock ACM's UpdateCertificateOptions.
+type MockACMUpdateCertificateOptionsAPI func(ctx context.Context, params *acm.UpdateCertificateOptionsInput, optFns ...func(*acm.Options)) (*acm.UpdateCertificateOptionsOutput, error)
+
+// MockACMRenewCertificateAPI is a type that represents a function that mock ACM's RenewCertificate.
+type MockACMRenewCertificateAPI func(ctx context.Context, params *acm.RenewCertificateInput, optFns ...func(*acm.Options)) (*acm.RenewCertificateOutput, error)
+
+// MockACMResendValidationEmailAPI is a type that represents a function that mock ACM's ResendValidationEmail.
+type MockACMResendValidationEmailAPI func(ctx context.Context, params *acm.ResendValidationEmailInput, optFns ...func(*acm.Options)) (*acm.ResendValidationEmailOutput, error)
+
 // DescribeCertificate returns a function that mock original of ACM DescribeCertificate.
 func (m MockACMAPI) DescribeCertificate(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
 	return m.DescribeCertificateAPI(ctx, params, optFns...)
@@ -48,5 +84,45 @@ func (m MockACMAPI) DeleteCertificate(ctx context.Context, params *acm.DeleteCer
 
 // RequestCertificate returns a function that mock original of ACM RequestCertificate.
 func (m MockACMAPI) RequestCertificate(ctx context.Context, params *acm.RequestCertificateInput, optFns ...func(*acm.Options)) (*acm.RequestCertificateOutput, error) {
-	return m.RequestCertificate(ctx, params, optFns...)
+	return m.RequestCertificateAPI(ctx, params, optFns...)
+}
+
+// ImportCertificate returns a function that mock original of ACM ImportCertificate.
+func (m MockACMAPI) ImportCertificate(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+	return m.ImportCertificateAPI(ctx, params, optFns...)
+}
+
+// ExportCertificate returns a function that mock original of ACM ExportCertificate.
+func (m MockACMAPI) ExportCertificate(ctx context.Context, params *acm.ExportCertificateInput, optFns ...func(*acm.Options)) (*acm.ExportCertificateOutput, error) {
+	return m.ExportCertificateAPI(ctx, params, optFns...)
+}
+
+// ListTagsForCertificate returns a function that mock original of ACM ListTagsForCertificate.
+func (m MockACMAPI) ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
+	return m.ListTagsForCertificateAPI(ctx, params, optFns...)
+}
+
+// AddTagsToCertificate returns a function that mock original of ACM AddTagsToCertificate.
+func (m MockACMAPI) AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+	return m.AddTagsToCertificateAPI(ctx, params, optFns...)
+}
+
+// RemoveTagsFromCertificate returns a function that mock original of ACM RemoveTagsFromCertificate.
+func (m MockACMAPI) RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+	return m.RemoveTagsFromCertificateAPI(ctx, params, optFns...)
+}
+
+// UpdateCertificateOptions returns a function that mock original of ACM UpdateCertificateOptions.
+func (m MockACMAPI) UpdateCertificateOptions(ctx context.Context, params *acm.UpdateCertificateOptionsInput, optFns ...func(*acm.Options)) (*acm.UpdateCertificateOptionsOutput, error) {
+	return m.UpdateCertificateOptionsAPI(ctx, params, optFns...)
+}
+
+// RenewCertificate returns a function that mock original of ACM RenewCertificate.
+func (m MockACMAPI) RenewCertificate(ctx context.Context, params *acm.RenewCertificateInput, optFns ...func(*acm.Options)) (*acm.RenewCertificateOutput, error) {
+	return m.RenewCertificateAPI(ctx, params, optFns...)
+}
+
+// ResendValidationEmail returns a function that mock original of ACM ResendValidationEmail.
+func (m MockACMAPI) ResendValidationEmail(ctx context.Context, params *acm.ResendValidationEmailInput, optFns ...func(*acm.Options)) (*acm.ResendValidationEmailOutput, error) {
+	return m.ResendValidationEmailAPI(ctx, params, optFns...)
 }
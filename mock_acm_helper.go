@@ -17,6 +17,15 @@ func NewMockACMAPI(mockParams []MockACMParams) MockACMAPI {
 		ListCertificatesAPI:    NewMockACMListCertificatesAPI(mockParams),
 		DeleteCertificateAPI:   NewMockACMDeleteCertificateAPI(mockParams),
 		RequestCertificateAPI:  NewMockACMRequestCertificateAPI(mockParams),
+		ImportCertificateAPI:   NewMockACMImportCertificateAPI(mockParams),
+		ExportCertificateAPI:   NewMockACMExportCertificateAPI(mockParams),
+
+		ListTagsForCertificateAPI:    NewMockACMListTagsForCertificateAPI(mockParams),
+		AddTagsToCertificateAPI:      NewMockACMAddTagsToCertificateAPI(mockParams),
+		RemoveTagsFromCertificateAPI: NewMockACMRemoveTagsFromCertificateAPI(mockParams),
+		UpdateCertificateOptionsAPI:  NewMockACMUpdateCertificateOptionsAPI(mockParams),
+		RenewCertificateAPI:          NewMockACMRenewCertificateAPI(mockParams),
+		ResendValidationEmailAPI:     NewMockACMResendValidationEmailAPI(mockParams),
 	}
 }
 
@@ -33,16 +42,69 @@ func NewMockACMDescribeCertificateAPI(mockParams []MockACMParams) MockACMDescrib
 				continue
 			}
 
-			dv := types.DomainValidation{
-				ValidationMethod: types.ValidationMethod(mp.Certificate.ValidationMethod),
+			// subject alternative names share the record of the domain name like wildcards
+			var (
+				sans []string
+				dvs  []types.DomainValidation
+			)
+			for _, d := range append([]string{mp.Certificate.DomainName}, mp.Certificate.SubjectAlternativeNames...) {
+				dv := types.DomainValidation{
+					ValidationMethod: types.ValidationMethod(mp.Certificate.ValidationMethod),
+				}
+				if mp.Certificate.ValidationMethod == string(types.ValidationMethodDns) {
+					dv.DomainName = aws.String(d)
+					dv.ValidationDomain = aws.String(mp.Certificate.ValidationRecordSet.HostedDomainName)
+					dv.ResourceRecord = &types.ResourceRecord{
+						Name:  aws.String(mp.Certificate.ValidationRecordSet.Name),
+						Value: aws.String(mp.Certificate.ValidationRecordSet.Value),
+						Type:  types.RecordType(mp.Certificate.ValidationRecordSet.Type),
+					}
+				}
+				if mp.Certificate.ValidationMethod == string(types.ValidationMethodEmail) {
+					dv.DomainName = aws.String(d)
+					dv.ValidationDomain = aws.String(d)
+					dv.ValidationEmails = mp.Certificate.ValidationEmails
+				}
+				dvs = append(dvs, dv)
+			}
+			if len(mp.Certificate.SubjectAlternativeNames) > 0 {
+				sans = append([]string{mp.Certificate.DomainName}, mp.Certificate.SubjectAlternativeNames...)
 			}
-			if mp.Certificate.ValidationMethod == string(types.ValidationMethodDns) {
-				dv.DomainName = aws.String(mp.Certificate.DomainName)
-				dv.ValidationDomain = aws.String(mp.Certificate.ValidationRecordSet.HostedDomainName)
-				dv.ResourceRecord = &types.ResourceRecord{
-					Name:  aws.String(mp.Certificate.ValidationRecordSet.Name),
-					Value: aws.String(mp.Certificate.ValidationRecordSet.Value),
-					Type:  types.RecordType(mp.Certificate.ValidationRecordSet.Type),
+
+			var options *types.CertificateOptions
+			if mp.Certificate.TransparencyLogging != "" {
+				options = &types.CertificateOptions{
+					CertificateTransparencyLoggingPreference: types.CertificateTransparencyLoggingPreference(mp.Certificate.TransparencyLogging),
+				}
+			}
+
+			var caArn *string
+			if mp.Certificate.CertificateAuthorityArn != "" {
+				caArn = aws.String(mp.Certificate.CertificateAuthorityArn)
+			}
+
+			var renewal *types.RenewalSummary
+			if rs := mp.RenewalSummary; rs != nil {
+				renewal = &types.RenewalSummary{
+					RenewalStatus:       types.RenewalStatus(rs.Status),
+					RenewalStatusReason: types.FailureReason(rs.Reason),
+					UpdatedAt:           rs.UpdatedAt,
+				}
+				for _, v := range rs.DomainValidations {
+					dv := types.DomainValidation{
+						DomainName:       aws.String(v.DomainName),
+						ValidationDomain: aws.String(v.ValidationDomain),
+						ValidationMethod: types.ValidationMethod(v.ValidationMethod),
+						ValidationStatus: types.DomainStatus(v.ValidationStatus),
+					}
+					if v.ValidationRecordSet != nil {
+						dv.ResourceRecord = &types.ResourceRecord{
+							Name:  aws.String(v.ValidationRecordSet.Name),
+							Value: aws.String(v.ValidationRecordSet.Value),
+							Type:  types.RecordType(v.ValidationRecordSet.Type),
+						}
+					}
+					renewal.DomainValidationOptions = append(renewal.DomainValidationOptions, dv)
 				}
 			}
 
@@ -50,17 +112,26 @@ func NewMockACMDescribeCertificateAPI(mockParams []MockACMParams) MockACMDescrib
 				Certificate: &types.CertificateDetail{
 					CertificateArn:          aws.String(mp.Certificate.Arn),
 					DomainName:              aws.String(mp.Certificate.DomainName),
+					SubjectAlternativeNames: sans,
 					Status:                  types.CertificateStatus(mp.Certificate.Status),
 					Type:                    types.CertificateType(mp.Certificate.Type),
+					KeyAlgorithm:            types.KeyAlgorithm(mp.Certificate.KeyAlgorithm),
 					FailureReason:           types.FailureReason(mp.Certificate.FailureReason),
-					DomainValidationOptions: []types.DomainValidation{dv},
+					DomainValidationOptions: dvs,
+					Options:                 options,
+					CertificateAuthorityArn: caArn,
+					RenewalEligibility:      types.RenewalEligibility(mp.Certificate.RenewalEligibility),
+					RenewalSummary:          renewal,
+					CreatedAt:               mp.Certificate.CreatedAt,
+					NotAfter:                mp.Certificate.NotAfter,
+					InUseBy:                 mp.Certificate.InUseBy,
 				},
 			}
 		}
 
 		dco := availableCertificates[*params.CertificateArn]
 		if dco == nil {
-			return nil, fmt.Errorf("certificate arn not found arn: %s", *params.CertificateArn)
+			return nil, &types.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("certificate arn not found arn: %s", *params.CertificateArn))}
 		}
 
 		return dco, nil
@@ -73,9 +144,14 @@ func NewMockACMListCertificatesAPI(mockParams []MockACMParams) MockACMListCertif
 		var csList []types.CertificateSummary
 
 		for _, mp := range mockParams {
+			var sans []string
+			if len(mp.Certificate.SubjectAlternativeNames) > 0 {
+				sans = append([]string{mp.Certificate.DomainName}, mp.Certificate.SubjectAlternativeNames...)
+			}
 			csList = append(csList, types.CertificateSummary{
-				CertificateArn: aws.String(mp.Certificate.Arn),
-				DomainName:     aws.String(mp.Certificate.DomainName),
+				CertificateArn:                  aws.String(mp.Certificate.Arn),
+				DomainName:                      aws.String(mp.Certificate.DomainName),
+				SubjectAlternativeNameSummaries: sans,
 			})
 		}
 
@@ -97,6 +173,9 @@ func NewMockACMDeleteCertificateAPI(mockParams []MockACMParams) MockACMDeleteCer
 		if _, ok := availableCertificates[*params.CertificateArn]; !ok {
 			return nil, fmt.Errorf("certificate arn not found arn: %s", *params.CertificateArn)
 		}
+		if mp := findMockACMParams(mockParams, *params.CertificateArn); len(mp.Certificate.InUseBy) > 0 {
+			return nil, &types.ResourceInUseException{Message: aws.String("certificate is in use")}
+		}
 
 		return &acm.DeleteCertificateOutput{}, nil
 	})
@@ -105,6 +184,179 @@ func NewMockACMDeleteCertificateAPI(mockParams []MockACMParams) MockACMDeleteCer
 // NewMockACMRequestCertificateAPI returns MockACMRequestCertificateAPI
 func NewMockACMRequestCertificateAPI(mockParams []MockACMParams) MockACMRequestCertificateAPI {
 	return MockACMRequestCertificateAPI(func(ctx context.Context, params *acm.RequestCertificateInput, optFns ...func(*acm.Options)) (*acm.RequestCertificateOutput, error) {
-		return &acm.RequestCertificateOutput{}, nil
+		if params.DomainName == nil {
+			return nil, errors.New("expect DomainName to not be nil")
+		}
+
+		// private certificates are requested only from certificate authorities
+		private := params.CertificateAuthorityArn != nil
+		for _, mp := range mockParams {
+			if mp.Certificate.Type == string(types.CertificateTypeImported) || (mp.Certificate.Type == string(types.CertificateTypePrivate)) != private {
+				continue
+			}
+			if mp.Certificate.DomainName == *params.DomainName {
+				return &acm.RequestCertificateOutput{
+					CertificateArn: aws.String(mp.Certificate.Arn),
+				}, nil
+			}
+		}
+
+		return nil, fmt.Errorf("domain name not available domain: %s", *params.DomainName)
+	})
+}
+
+// NewMockACMImportCertificateAPI returns MockACMImportCertificateAPI
+func NewMockACMImportCertificateAPI(mockParams []MockACMParams) MockACMImportCertificateAPI {
+	return MockACMImportCertificateAPI(func(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error) {
+		if len(params.Certificate) == 0 || len(params.PrivateKey) == 0 {
+			return nil, errors.New("expect Certificate and PrivateKey to not be empty")
+		}
+
+		for _, mp := range mockParams {
+			if mp.Certificate.Type == string(types.CertificateTypeImported) {
+				return &acm.ImportCertificateOutput{
+					CertificateArn: aws.String(mp.Certificate.Arn),
+				}, nil
+			}
+		}
+
+		return nil, errors.New("imported certificate not available")
+	})
+}
+
+// NewMockACMExportCertificateAPI returns MockACMExportCertificateAPI
+func NewMockACMExportCertificateAPI(mockParams []MockACMParams) MockACMExportCertificateAPI {
+	return MockACMExportCertificateAPI(func(ctx context.Context, params *acm.ExportCertificateInput, optFns ...func(*acm.Options)) (*acm.ExportCertificateOutput, error) {
+		if len(params.Passphrase) < 4 {
+			return nil, errors.New("expect Passphrase to have at least 4 characters")
+		}
+
+		mp := findMockACMParams(mockParams, aws.ToString(params.CertificateArn))
+		if mp == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+		if mp.Certificate.Type != string(types.CertificateTypePrivate) {
+			return nil, fmt.Errorf("certificate is not private arn: %s", mp.Certificate.Arn)
+		}
+
+		return &acm.ExportCertificateOutput{
+			Certificate:      aws.String(mp.ExportedCertificate.Certificate),
+			CertificateChain: aws.String(mp.ExportedCertificate.CertificateChain),
+			PrivateKey:       aws.String(mp.ExportedCertificate.PrivateKey),
+		}, nil
 	})
 }
+
+// NewMockACMListTagsForCertificateAPI returns MockACMListTagsForCertificateAPI
+func NewMockACMListTagsForCertificateAPI(mockParams []MockACMParams) MockACMListTagsForCertificateAPI {
+	return MockACMListTagsForCertificateAPI(func(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error) {
+		mp := findMockACMParams(mockParams, aws.ToString(params.CertificateArn))
+		if mp == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+
+		out := acm.ListTagsForCertificateOutput{}
+		for k, v := range mp.Tags {
+			out.Tags = append(out.Tags, types.Tag{
+				Key:   aws.String(k),
+				Value: aws.String(v),
+			})
+		}
+
+		return &out, nil
+	})
+}
+
+// NewMockACMAddTagsToCertificateAPI returns MockACMAddTagsToCertificateAPI
+func NewMockACMAddTagsToCertificateAPI(mockParams []MockACMParams) MockACMAddTagsToCertificateAPI {
+	return MockACMAddTagsToCertificateAPI(func(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error) {
+		if findMockACMParams(mockParams, aws.ToString(params.CertificateArn)) == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+		if len(params.Tags) == 0 {
+			return nil, errors.New("expect Tags to not be empty")
+		}
+
+		return &acm.AddTagsToCertificateOutput{}, nil
+	})
+}
+
+// NewMockACMRemoveTagsFromCertificateAPI returns MockACMRemoveTagsFromCertificateAPI
+func NewMockACMRemoveTagsFromCertificateAPI(mockParams []MockACMParams) MockACMRemoveTagsFromCertificateAPI {
+	return MockACMRemoveTagsFromCertificateAPI(func(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error) {
+		if findMockACMParams(mockParams, aws.ToString(params.CertificateArn)) == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+		if len(params.Tags) == 0 {
+			return nil, errors.New("expect Tags to not be empty")
+		}
+
+		return &acm.RemoveTagsFromCertificateOutput{}, nil
+	})
+}
+
+// NewMockACMUpdateCertificateOptionsAPI returns MockACMUpdateCertificateOptionsAPI
+func NewMockACMUpdateCertificateOptionsAPI(mockParams []MockACMParams) MockACMUpdateCertificateOptionsAPI {
+	return MockACMUpdateCertificateOptionsAPI(func(ctx context.Context, params *acm.UpdateCertificateOptionsInput, optFns ...func(*acm.Options)) (*acm.UpdateCertificateOptionsOutput, error) {
+		if findMockACMParams(mockParams, aws.ToString(params.CertificateArn)) == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+		if params.Options == nil {
+			return nil, errors.New("expect Options to not be nil")
+		}
+
+		return &acm.UpdateCertificateOptionsOutput{}, nil
+	})
+}
+
+// NewMockACMRenewCertificateAPI returns MockACMRenewCertificateAPI
+func NewMockACMRenewCertificateAPI(mockParams []MockACMParams) MockACMRenewCertificateAPI {
+	return MockACMRenewCertificateAPI(func(ctx context.Context, params *acm.RenewCertificateInput, optFns ...func(*acm.Options)) (*acm.RenewCertificateOutput, error) {
+		mp := findMockACMParams(mockParams, aws.ToString(params.CertificateArn))
+		if mp == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+		if mp.Certificate.Type != string(types.CertificateTypePrivate) {
+			return nil, errors.New("only private certificates can be renewed")
+		}
+
+		return &acm.RenewCertificateOutput{}, nil
+	})
+}
+
+// NewMockACMResendValidationEmailAPI returns MockACMResendValidationEmailAPI
+func NewMockACMResendValidationEmailAPI(mockParams []MockACMParams) MockACMResendValidationEmailAPI {
+	return MockACMResendValidationEmailAPI(func(ctx context.Context, params *acm.ResendValidationEmailInput, optFns ...func(*acm.Options)) (*acm.ResendValidationEmailOutput, error) {
+		mp := findMockACMParams(mockParams, aws.ToString(params.CertificateArn))
+		if mp == nil {
+			return nil, fmt.Errorf("certificate arn not found arn: %s", aws.ToString(params.CertificateArn))
+		}
+		if mp.Certificate.Status != string(types.CertificateStatusPendingValidation) {
+			return nil, errors.New("certificate is not pending validation")
+		}
+
+		domain := aws.ToString(params.Domain)
+		found := false
+		for _, d := range append([]string{mp.Certificate.DomainName}, mp.Certificate.SubjectAlternativeNames...) {
+			found = found || d == domain
+		}
+		if !found {
+			return nil, fmt.Errorf("domain is not in the certificate: %s", domain)
+		}
+		if params.ValidationDomain == nil {
+			return nil, errors.New("expect ValidationDomain to not be nil")
+		}
+
+		return &acm.ResendValidationEmailOutput{}, nil
+	})
+}
+
+// Returns the mock params of the certificate ARN, or nil if not found.
+func findMockACMParams(mockParams []MockACMParams, arn string) *MockACMParams {
+	for i := range mockParams {
+		if mockParams[i].Certificate.Arn != "" && mockParams[i].Certificate.Arn == arn {
+			return &mockParams[i]
+		}
+	}
+	return nil
+}
@@ -47,6 +47,10 @@ func NewMockListResourceRecordSetsAPI(mockParams []MockRoute53Params) MockListRe
 
 		available := map[string]*types.ResourceRecordSet{}
 		for _, p := range mockParams {
+			// a record expected to be created or repaired does not exist yet
+			if p.ChangeAction == types.ChangeActionCreate || p.ChangeAction == types.ChangeActionUpsert {
+				continue
+			}
 			available[p.RecordSet.Name] = &types.ResourceRecordSet{
 				Name: aws.String(p.RecordSet.Name),
 				TTL:  aws.Int64(p.RecordSet.TTL),
@@ -59,12 +63,9 @@ func NewMockListResourceRecordSetsAPI(mockParams []MockRoute53Params) MockListRe
 			}
 		}
 
-		rrs := available[aws.ToString(params.StartRecordName)]
-		if rrs == nil {
-			return nil, fmt.Errorf("resource record sets not exists name: %s", *params.StartRecordName)
+		if rrs := available[aws.ToString(params.StartRecordName)]; rrs != nil {
+			out.ResourceRecordSets = append(out.ResourceRecordSets, *rrs)
 		}
-
-		out.ResourceRecordSets = append(out.ResourceRecordSets, *rrs)
 		return &out, nil
 	})
 }
@@ -0,0 +1,345 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/account"
+	accountTypes "github.com/aws/aws-sdk-go-v2/service/account/types"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// ACMRegions is a fixed list of the commercial regions where ACM is available, as of the time of writing.
+// NewMultiRegionGoACM uses it for all regions only if the regions enabled for the account cannot be listed,
+// such as without the permission of account:ListRegions. Then opt-in regions that are not enabled return errors,
+// and regions launched later are not included.
+var ACMRegions = []string{
+	"us-east-1",
+	"us-east-2",
+	"us-west-1",
+	"us-west-2",
+	"af-south-1",
+	"ap-east-1",
+	"ap-south-1",
+	"ap-south-2",
+	"ap-northeast-1",
+	"ap-northeast-2",
+	"ap-northeast-3",
+	"ap-southeast-1",
+	"ap-southeast-2",
+	"ap-southeast-3",
+	"ap-southeast-4",
+	"ap-southeast-5",
+	"ap-southeast-7",
+	"ca-central-1",
+	"ca-west-1",
+	"eu-central-1",
+	"eu-central-2",
+	"eu-west-1",
+	"eu-west-2",
+	"eu-west-3",
+	"eu-south-1",
+	"eu-south-2",
+	"eu-north-1",
+	"il-central-1",
+	"me-south-1",
+	"me-central-1",
+	"mx-central-1",
+	"sa-east-1",
+}
+
+// ListEnabledRegions returns the sorted regions enabled for the account, including opt-in regions enabled by the account.
+// ACM is available in all of them.
+func ListEnabledRegions(ctx context.Context, api AccountListRegionsAPI) ([]string, error) {
+	in := account.ListRegionsInput{
+		RegionOptStatusContains: []accountTypes.RegionOptStatus{
+			accountTypes.RegionOptStatusEnabled,
+			accountTypes.RegionOptStatusEnabledByDefault,
+		},
+	}
+
+	regions := []string{}
+	for {
+		out, err := api.ListRegions(ctx, &in)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range out.Regions {
+			regions = append(regions, aws.ToString(r.RegionName))
+		}
+
+		if aws.ToString(out.NextToken) == "" {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// Returns the regions enabled for the account, or ACMRegions if they cannot be listed.
+func allRegions(ctx context.Context, api AccountListRegionsAPI) []string {
+	regions, err := ListEnabledRegions(ctx, api)
+	if err != nil || len(regions) == 0 {
+		return ACMRegions
+	}
+	return regions
+}
+
+// RegionalACMAPI is a map of a region and the ACM client of the region.
+type RegionalACMAPI map[string]ACMAPI
+
+// RegionsError is an error that represents errors that occurred in some regions.
+type RegionsError struct {
+	Errors map[string]error
+}
+
+func (e *RegionsError) Error() string {
+	regions := make([]string, 0, len(e.Errors))
+	for r := range e.Errors {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+
+	msgs := make([]string, 0, len(regions))
+	for _, r := range regions {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", r, e.Errors[r]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// MultiRegionGoACM is a structure that wraps GoACM objects of multiple regions.
+type MultiRegionGoACM struct {
+	Clients map[string]*GoACM
+	Regions []string
+}
+
+// NewMultiRegionGoACM returns a new MultiRegionGoACM object.
+// If regions is empty, the regions enabled for the account are used, or ACMRegions if they cannot be listed.
+func NewMultiRegionGoACM(ctx context.Context, regions []string, optFns ...func(*GoACMOptions)) (*MultiRegionGoACM, error) {
+	if len(regions) == 0 {
+		o := GoACMOptions{}
+		for _, fn := range optFns {
+			fn(&o)
+		}
+		cfg, _, err := resolveConfigs(ctx, "", o)
+		if err != nil {
+			return nil, err
+		}
+		// the account API is global, and is called in us-east-1 without a default region
+		if cfg.Region == "" {
+			cfg.Region = "us-east-1"
+		}
+		regions = allRegions(ctx, account.NewFromConfig(cfg))
+	}
+
+	m := MultiRegionGoACM{
+		Clients: map[string]*GoACM{},
+		Regions: regions,
+	}
+	for _, r := range regions {
+		g, err := NewGoACM(ctx, r, optFns...)
+		if err != nil {
+			return nil, err
+		}
+		m.Clients[r] = g
+
+		// the accounts are the same in all regions, so they are resolved once
+		if len(m.Clients) == 1 {
+			optFns = append(optFns[:len(optFns):len(optFns)], WithAccountIDs(g.ACMAccountID, g.Route53AccountID))
+		}
+	}
+
+	return &m, nil
+}
+
+// ACMAPIs returns the ACM clients of all regions.
+func (m *MultiRegionGoACM) ACMAPIs() RegionalACMAPI {
+	apis := RegionalACMAPI{}
+	for r, g := range m.Clients {
+		apis[r] = g.ACMAPI()
+	}
+	return apis
+}
+
+// Route53API returns the Route 53 client. Route 53 is a global service,
+// so the client of any region can be used.
+func (m *MultiRegionGoACM) Route53API() Route53API {
+	for _, r := range m.Regions {
+		if g, ok := m.Clients[r]; ok {
+			return g.Route53API()
+		}
+	}
+	return nil
+}
+
+// ListCertificates returns list of certificate in all regions.
+func (m *MultiRegionGoACM) ListCertificates(ctx context.Context) ([]Certificate, error) {
+	return ListCertificatesInRegions(ctx, m.ACMAPIs())
+}
+
+// SearchCertificates returns list of certificate in all regions whose domain name matches the pattern.
+func (m *MultiRegionGoACM) SearchCertificates(ctx context.Context, pattern string) ([]Certificate, error) {
+	return SearchCertificatesInRegions(ctx, m.ACMAPIs(), pattern)
+}
+
+// GetCertificate returns the details of the certificate in the region of the ARN.
+func (m *MultiRegionGoACM) GetCertificate(ctx context.Context, certificateArn string) (Certificate, error) {
+	api, err := m.ACMAPIs().ForArn(certificateArn)
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	c, err := GetCertificate(ctx, api, certificateArn)
+	if err != nil {
+		return Certificate{}, err
+	}
+	c.Region = arnRegion(certificateArn)
+
+	return c, nil
+}
+
+// DeleteCertificate deletes the certificate in the region of the ARN.
+func (m *MultiRegionGoACM) DeleteCertificate(ctx context.Context, certificateArn string, optFns ...func(*DeleteCertificateOptions)) error {
+	api, err := m.ACMAPIs().ForArn(certificateArn)
+	if err != nil {
+		return err
+	}
+
+	regions := func(o *DeleteCertificateOptions) {
+		o.Regions = m.ACMAPIs()
+	}
+	return DeleteCertificate(ctx, api, m.Route53API(), certificateArn, append([]func(*DeleteCertificateOptions){regions}, optFns...)...)
+}
+
+// ForArn returns the ACM client of the region encoded in the certificate ARN.
+func (r RegionalACMAPI) ForArn(certificateArn string) (ACMAPI, error) {
+	a, err := arn.Parse(certificateArn)
+	if err != nil {
+		return nil, err
+	}
+
+	api, ok := r[a.Region]
+	if !ok {
+		return nil, fmt.Errorf("ACM client of the region does not exists: %s", a.Region)
+	}
+
+	return api, nil
+}
+
+// ListCertificatesInRegions returns list of certificate in all regions concurrently.
+// If it fails in some regions, it returns certificates of the other regions with *RegionsError.
+// A region where some certificates cannot be described has *CertificatesError, and the other certificates are returned.
+func ListCertificatesInRegions(ctx context.Context, apis RegionalACMAPI) ([]Certificate, error) {
+	return inRegions(ctx, apis, ListCertificates)
+}
+
+// SearchCertificates returns list of certificate whose domain name matches the pattern.
+// The pattern is a shell pattern such as "*.example.com", and is matched case-insensitively.
+// If some certificates cannot be described, it returns the other certificates with *CertificatesError.
+func SearchCertificates(ctx context.Context, api ACMAPI, pattern string) ([]Certificate, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	summary, err := ListCertificateSummaries(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []acmTypes.CertificateSummary{}
+	for _, s := range summary {
+		if matchDomainName(pattern, aws.ToString(s.DomainName)) {
+			matched = append(matched, s)
+		}
+	}
+
+	return describeCertificates(ctx, api, matched)
+}
+
+// SearchCertificatesInRegions returns list of certificate in all regions whose domain name matches the pattern.
+// If it fails in some regions, it returns certificates of the other regions with *RegionsError.
+func SearchCertificatesInRegions(ctx context.Context, apis RegionalACMAPI, pattern string) ([]Certificate, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	return inRegions(ctx, apis, func(ctx context.Context, api ACMAPI) ([]Certificate, error) {
+		return SearchCertificates(ctx, api, pattern)
+	})
+}
+
+// Call fn for each region concurrently, and return certificates ordered by region with Region set.
+func inRegions(ctx context.Context, apis RegionalACMAPI, fn func(context.Context, ACMAPI) ([]Certificate, error)) ([]Certificate, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = map[string][]Certificate{}
+		errs    = map[string]error{}
+	)
+
+	for region, api := range apis {
+		wg.Add(1)
+		go func(region string, api ACMAPI) {
+			defer wg.Done()
+
+			cList, err := fn(ctx, api)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[region] = err
+				// certificates that were described are returned
+				var ce *CertificatesError
+				if !errors.As(err, &ce) {
+					return
+				}
+			}
+			results[region] = cList
+		}(region, api)
+	}
+	wg.Wait()
+
+	regions := make([]string, 0, len(results))
+	for r := range results {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+
+	var cList []Certificate
+	for _, r := range regions {
+		for _, c := range results[r] {
+			c.Region = r
+			cList = append(cList, c)
+		}
+	}
+
+	if len(errs) > 0 {
+		return cList, &RegionsError{Errors: errs}
+	}
+
+	return cList, nil
+}
+
+// Returns true if the domain name matches the shell pattern case-insensitively.
+func matchDomainName(pattern, domainName string) bool {
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(domainName))
+	return ok
+}
+
+// Returns the region encoded in the ARN, or "" if the ARN is invalid.
+func arnRegion(s string) string {
+	a, err := arn.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return a.Region
+}
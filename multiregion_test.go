@@ -0,0 +1,301 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/account"
+	accountTypes "github.com/aws/aws-sdk-go-v2/service/account/types"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func regionalMockParams(region string, domains ...string) []goacm.MockACMParams {
+	mp := []goacm.MockACMParams{}
+	for _, d := range domains {
+		mp = append(mp, goacm.MockACMParams{
+			Certificate: goacm.Certificate{
+				Arn:        "arn:aws:acm:" + region + ":000000000000:certificate/" + d,
+				DomainName: d,
+				Status:     string(types.CertificateStatusIssued),
+				Type:       string(types.CertificateTypeAmazonIssued),
+			},
+		})
+	}
+	return mp
+}
+
+func Test_ListCertificatesInRegions(t *testing.T) {
+	failing := goacm.NewMockACMAPI(nil)
+	failing.ListCertificatesAPI = func(ctx context.Context, params *acm.ListCertificatesInput, optFns ...func(*acm.Options)) (*acm.ListCertificatesOutput, error) {
+		return nil, errors.New("region is not enabled")
+	}
+	partial := goacm.NewMockACMAPI(regionalMockParams("af-south-1", "app.example.com", "api.example.com"))
+	partial.DescribeCertificateAPI = failingDescribe(partial.DescribeCertificateAPI, "arn:aws:acm:af-south-1:000000000000:certificate/api.example.com")
+
+	cases := []struct {
+		name          string
+		apis          goacm.RegionalACMAPI
+		wantErr       bool
+		expectRegions []string
+		expectDomains []string
+	}{
+		{
+			name: "normal",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1":      goacm.NewMockACMAPI(regionalMockParams("us-east-1", "cdn.example.com")),
+				"ap-northeast-1": goacm.NewMockACMAPI(regionalMockParams("ap-northeast-1", "app.example.com", "api.example.com")),
+			},
+			wantErr:       false,
+			expectRegions: []string{"ap-northeast-1", "ap-northeast-1", "us-east-1"},
+			expectDomains: []string{"app.example.com", "api.example.com", "cdn.example.com"},
+		},
+		{
+			name: "error: failed in a region",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1":  goacm.NewMockACMAPI(regionalMockParams("us-east-1", "cdn.example.com")),
+				"af-south-1": failing,
+			},
+			wantErr:       true,
+			expectRegions: []string{"us-east-1"},
+			expectDomains: []string{"cdn.example.com"},
+		}, {
+			name: "error: failed to describe a certificate in a region",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1":  goacm.NewMockACMAPI(regionalMockParams("us-east-1", "cdn.example.com")),
+				"af-south-1": partial,
+			},
+			wantErr:       true,
+			expectRegions: []string{"af-south-1", "us-east-1"},
+			expectDomains: []string{"app.example.com", "cdn.example.com"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			cList, err := goacm.ListCertificatesInRegions(context.TODO(), c.apis)
+			if c.wantErr {
+				var re *goacm.RegionsError
+				assert.True(tt, errors.As(err, &re))
+				assert.Contains(tt, re.Errors, "af-south-1")
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			regions := []string{}
+			domains := []string{}
+			for _, cert := range cList {
+				regions = append(regions, cert.Region)
+				domains = append(domains, cert.DomainName)
+			}
+			assert.Equal(tt, c.expectRegions, regions)
+			assert.Equal(tt, c.expectDomains, domains)
+		})
+	}
+}
+
+func Test_SearchCertificatesInRegions(t *testing.T) {
+	usEast1 := goacm.NewMockACMAPI(regionalMockParams("us-east-1", "cdn.example.com", "cdn.example.net", "www.example.net"))
+	usEast1.DescribeCertificateAPI = failingDescribe(usEast1.DescribeCertificateAPI, "arn:aws:acm:us-east-1:000000000000:certificate/www.example.net")
+	apis := goacm.RegionalACMAPI{
+		"us-east-1":      usEast1,
+		"ap-northeast-1": goacm.NewMockACMAPI(regionalMockParams("ap-northeast-1", "app.example.com")),
+	}
+
+	cases := []struct {
+		name          string
+		pattern       string
+		wantErr       bool
+		expectDomains []string
+	}{
+		{
+			name:          "normal: wildcard",
+			pattern:       "*.example.com",
+			expectDomains: []string{"app.example.com", "cdn.example.com"},
+		},
+		{
+			name:          "normal: case-insensitive",
+			pattern:       "CDN.example.NET",
+			expectDomains: []string{"cdn.example.net"},
+		},
+		{
+			name:          "normal: not matched",
+			pattern:       "*.example.org",
+			expectDomains: []string{},
+		},
+		{
+			name:          "error: failed to describe a matched certificate",
+			pattern:       "*.example.net",
+			wantErr:       true,
+			expectDomains: []string{"cdn.example.net"},
+		},
+		{
+			name:    "error: invalid pattern",
+			pattern: "[",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			cList, err := goacm.SearchCertificatesInRegions(context.TODO(), apis, c.pattern)
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			if c.expectDomains == nil {
+				return
+			}
+			domains := []string{}
+			for _, cert := range cList {
+				domains = append(domains, cert.DomainName)
+			}
+			assert.Equal(tt, c.expectDomains, domains)
+		})
+	}
+}
+
+// Returns the describe API that fails for the ARN.
+func failingDescribe(describe goacm.MockACMDescribeCertificateAPI, arn string) goacm.MockACMDescribeCertificateAPI {
+	return func(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+		if aws.ToString(params.CertificateArn) == arn {
+			return nil, errors.New("throttled")
+		}
+		return describe(ctx, params, optFns...)
+	}
+}
+
+func Test_RegionalACMAPI_ForArn(t *testing.T) {
+	usEast1 := goacm.NewMockACMAPI(regionalMockParams("us-east-1", "cdn.example.com"))
+	apis := goacm.RegionalACMAPI{
+		"us-east-1": usEast1,
+	}
+
+	cases := []struct {
+		name    string
+		arn     string
+		wantErr bool
+	}{
+		{
+			name: "normal",
+			arn:  "arn:aws:acm:us-east-1:000000000000:certificate/cdn.example.com",
+		},
+		{
+			name:    "error: region without client",
+			arn:     "arn:aws:acm:eu-west-1:000000000000:certificate/cdn.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "error: invalid arn",
+			arn:     "not-an-arn",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			api, err := apis.ForArn(c.arn)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			cert, err := goacm.GetCertificate(context.TODO(), api, c.arn)
+			assert.NoError(tt, err)
+			assert.Equal(tt, "cdn.example.com", cert.DomainName)
+		})
+	}
+}
+
+// mockListRegions is an account client that returns the pages of regions enabled for the account, or the error if set.
+type mockListRegions struct {
+	pages [][]string
+	err   error
+}
+
+func (m mockListRegions) ListRegions(ctx context.Context, params *account.ListRegionsInput, optFns ...func(*account.Options)) (*account.ListRegionsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(params.RegionOptStatusContains) != 2 {
+		return nil, errors.New("expect regions to be filtered by the statuses")
+	}
+
+	page := 0
+	if params.NextToken != nil {
+		page = 1
+	}
+	out := account.ListRegionsOutput{}
+	for _, r := range m.pages[page] {
+		out.Regions = append(out.Regions, accountTypes.Region{RegionName: aws.String(r), RegionOptStatus: accountTypes.RegionOptStatusEnabled})
+	}
+	if page+1 < len(m.pages) {
+		out.NextToken = aws.String("next")
+	}
+	return &out, nil
+}
+
+func Test_ListEnabledRegions(t *testing.T) {
+	cases := []struct {
+		name    string
+		api     mockListRegions
+		wantErr bool
+		expect  []string
+	}{
+		{
+			name:   "normal",
+			api:    mockListRegions{pages: [][]string{{"us-east-1", "ap-southeast-7"}, {"ap-northeast-1"}}},
+			expect: []string{"ap-northeast-1", "ap-southeast-7", "us-east-1"},
+		},
+		{
+			name:    "error: access denied",
+			api:     mockListRegions{err: errors.New("access denied")},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			regions, err := goacm.ListEnabledRegions(context.TODO(), c.api)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, regions)
+		})
+	}
+}
+
+func Test_allRegions(t *testing.T) {
+	cases := []struct {
+		name   string
+		api    mockListRegions
+		expect []string
+	}{
+		{
+			name:   "normal: enabled regions",
+			api:    mockListRegions{pages: [][]string{{"us-east-1", "mx-central-1"}}},
+			expect: []string{"mx-central-1", "us-east-1"},
+		},
+		{
+			name:   "normal: fixed list if the regions cannot be listed",
+			api:    mockListRegions{err: errors.New("access denied")},
+			expect: goacm.ACMRegions,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, goacm.ExportedAllRegions(context.TODO(), c.api))
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// Package notify sends alerts of ACM certificates to webhooks, such as certificates crossing expiry thresholds,
+// certificates that failed in validation, and records that validate the domains having been lost.
+//
+// Alerts that have been sent are recorded in a state for each notifier, so that the same alert is not repeated in every run.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+)
+
+// AlertKind is a type that represents a kind of alerts.
+type AlertKind string
+
+// Kinds of alerts.
+const (
+	// AlertKindExpiring means the certificate crossed an expiry threshold.
+	AlertKindExpiring AlertKind = "EXPIRING"
+
+	// AlertKindExpired means the certificate has expired.
+	AlertKindExpired AlertKind = "EXPIRED"
+
+	// AlertKindFailed means the certificate became FAILED or VALIDATION_TIMED_OUT.
+	AlertKindFailed AlertKind = "FAILED"
+
+	// AlertKindValidationRecordLost means a record that validates a domain of the certificate is missing or has another value.
+	AlertKindValidationRecordLost AlertKind = "VALIDATION_RECORD_LOST"
+)
+
+// DefaultThresholds are default expiry thresholds in days.
+var DefaultThresholds = []int{30, 14, 7, 1}
+
+// Alert is a structure that represents an alert of a certificate.
+type Alert struct {
+	Kind           AlertKind  `json:"kind" yaml:"kind"`
+	CertificateArn string     `json:"certificateArn" yaml:"certificateArn"`
+	DomainName     string     `json:"domainName" yaml:"domainName"`
+	Region         string     `json:"region,omitempty" yaml:"region,omitempty"`
+	Status         string     `json:"status,omitempty" yaml:"status,omitempty"`
+	FailureReason  string     `json:"failureReason,omitempty" yaml:"failureReason,omitempty"`
+	NotAfter       *time.Time `json:"notAfter,omitempty" yaml:"notAfter,omitempty"`
+	DaysLeft       int        `json:"daysLeft,omitempty" yaml:"daysLeft,omitempty"`
+
+	// Threshold is the expiry threshold in days that the certificate crossed.
+	Threshold int `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+
+	// RecordName is the name of the record that was lost.
+	RecordName string `json:"recordName,omitempty" yaml:"recordName,omitempty"`
+
+	Message string `json:"message" yaml:"message"`
+}
+
+// Key returns the key that identifies the alert in the state.
+// Expiry alerts of a renewed certificate have another key, because the time it expires changes.
+func (a Alert) Key() string {
+	parts := []string{string(a.Kind), a.CertificateArn}
+	switch a.Kind {
+	case AlertKindExpiring, AlertKindExpired:
+		parts = append(parts, fmt.Sprint(a.Threshold))
+		if a.NotAfter != nil {
+			parts = append(parts, a.NotAfter.UTC().Format(time.RFC3339))
+		}
+	case AlertKindFailed:
+		parts = append(parts, a.Status)
+	case AlertKindValidationRecordLost:
+		parts = append(parts, a.RecordName)
+	}
+	return strings.Join(parts, "|")
+}
+
+// AlertOptions is a structure that represents options for detecting alerts.
+type AlertOptions struct {
+	// Thresholds are expiry thresholds in days. Default is DefaultThresholds.
+	Thresholds []int
+
+	// Now is the time to detect alerts at. Default is the current time.
+	Now time.Time
+}
+
+// DetectAlerts returns the alerts of the certificates, and of the reports of CheckRenewalReadiness.
+// An expiry alert has the smallest threshold that the certificate crossed.
+func DetectAlerts(cList []goacm.Certificate, readiness []goacm.RenewalReadiness, optFns ...func(*AlertOptions)) []Alert {
+	o := AlertOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if len(o.Thresholds) == 0 {
+		o.Thresholds = DefaultThresholds
+	}
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+
+	thresholds := append([]int{}, o.Thresholds...)
+	sort.Ints(thresholds)
+
+	alerts := []Alert{}
+	for _, c := range cList {
+		switch c.Status {
+		case string(types.CertificateStatusFailed), string(types.CertificateStatusValidationTimedOut):
+			a := certificateAlert(AlertKindFailed, c)
+			a.Status = c.Status
+			a.FailureReason = c.FailureReason
+			a.Message = fmt.Sprintf("Certificate of %s is %s", c.DomainName, c.Status)
+			if c.FailureReason != "" {
+				a.Message += fmt.Sprintf(" (%s)", c.FailureReason)
+			}
+			alerts = append(alerts, a)
+			continue
+		}
+
+		if c.NotAfter == nil {
+			continue
+		}
+		left := c.NotAfter.Sub(o.Now)
+		days := goacm.DaysLeft(*c.NotAfter, o.Now)
+		if left <= 0 {
+			a := certificateAlert(AlertKindExpired, c)
+			a.DaysLeft = days
+			a.Message = fmt.Sprintf("Certificate of %s expired at %s", c.DomainName, c.NotAfter.UTC().Format(time.RFC3339))
+			alerts = append(alerts, a)
+			continue
+		}
+		for _, t := range thresholds {
+			if left > time.Duration(t)*24*time.Hour {
+				continue
+			}
+			a := certificateAlert(AlertKindExpiring, c)
+			a.DaysLeft = days
+			a.Threshold = t
+			a.Message = fmt.Sprintf("Certificate of %s expires in %d days at %s", c.DomainName, days, c.NotAfter.UTC().Format(time.RFC3339))
+			alerts = append(alerts, a)
+			break
+		}
+	}
+
+	for _, r := range readiness {
+		for _, rc := range r.Records {
+			if rc.Repaired || (rc.State != goacm.ValidationRecordStateMissing && rc.State != goacm.ValidationRecordStateMismatch) {
+				continue
+			}
+			alerts = append(alerts, Alert{
+				Kind:           AlertKindValidationRecordLost,
+				CertificateArn: r.CertificateArn,
+				DomainName:     r.DomainName,
+				RecordName:     rc.RecordSet.Name,
+				Message:        fmt.Sprintf("Record %s that validates %s is %s, and managed renewal will fail", rc.RecordSet.Name, r.DomainName, rc.State),
+			})
+		}
+	}
+
+	return alerts
+}
+
+func certificateAlert(kind AlertKind, c goacm.Certificate) Alert {
+	return Alert{
+		Kind:           kind,
+		CertificateArn: c.Arn,
+		DomainName:     c.DomainName,
+		Region:         c.Region,
+		NotAfter:       c.NotAfter,
+	}
+}
+
+// State is a structure that records the alerts that have been sent to each notifier by their keys.
+type State struct {
+	Sent map[string]time.Time `json:"sent"`
+}
+
+// LoadState loads the state from the file. If the file does not exist, it returns an empty state.
+func LoadState(path string) (*State, error) {
+	s := &State{Sent: map[string]time.Time{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if s.Sent == nil {
+		s.Sent = map[string]time.Time{}
+	}
+	return s, nil
+}
+
+// Save writes the state to the file atomically.
+func (s *State) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Returns the key of the alert sent to the notifier in the state.
+func sentKey(alertKey, notifierID string) string {
+	return alertKey + "#" + notifierID
+}
+
+// Returns the key of the alert of the key in the state.
+func alertKeyOf(sentKey string) string {
+	if i := strings.LastIndex(sentKey, "#"); i >= 0 {
+		return sentKey[:i]
+	}
+	return sentKey
+}
+
+// Resolve removes the records of alerts that are no longer detected, so that they are sent again if they recur.
+// Only the alerts of the certificates in cList are resolved, so that records of certificates that were not listed,
+// such as ones that failed to be described, are kept. Alerts of validation records are resolved only if recordsChecked is true,
+// because they are not detected without checking the records.
+func (s *State) Resolve(alerts []Alert, cList []goacm.Certificate, recordsChecked bool) {
+	detected := map[string]bool{}
+	for _, a := range alerts {
+		detected[a.Key()] = true
+	}
+	listed := map[string]bool{}
+	for _, c := range cList {
+		listed[c.Arn] = true
+	}
+
+	for key := range s.Sent {
+		alertKey := alertKeyOf(key)
+		parts := strings.SplitN(alertKey, "|", 3)
+		if detected[alertKey] || len(parts) < 2 || !listed[parts[1]] {
+			continue
+		}
+		if AlertKind(parts[0]) == AlertKindValidationRecordLost && !recordsChecked {
+			continue
+		}
+		delete(s.Sent, key)
+	}
+}
+
+// Notifier is an interface that sends an alert.
+// ID identifies the notifier in the state, so that alerts are recorded for each notifier, and must be stable across runs.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+	ID() string
+}
+
+// Notify sends the alerts to the notifiers that they are not recorded for in the state, and returns the alerts
+// that were sent to any notifier. An alert is recorded for each notifier that succeeded, so that it is retried
+// only for the failed notifiers in the next run. Records are not removed; call State.Resolve to remove the records
+// of alerts that are no longer detected. It returns an error that joins the errors of the notifiers.
+func Notify(ctx context.Context, alerts []Alert, notifiers []Notifier, state *State, now time.Time) ([]Alert, error) {
+	sent := []Alert{}
+	msgs := []string{}
+	for _, a := range alerts {
+		key := a.Key()
+		notified := false
+		for _, n := range notifiers {
+			sk := sentKey(key, n.ID())
+			if _, ok := state.Sent[sk]; ok {
+				continue
+			}
+			if err := n.Notify(ctx, a); err != nil {
+				msgs = append(msgs, fmt.Sprintf("%s: %s: %v", key, n.ID(), err))
+				continue
+			}
+			state.Sent[sk] = now
+			notified = true
+		}
+		if notified {
+			sent = append(sent, a)
+		}
+	}
+
+	if len(msgs) > 0 {
+		return sent, errors.New(strings.Join(msgs, "; "))
+	}
+	return sent, nil
+}
@@ -0,0 +1,312 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func days(n int) *time.Time {
+	return aws.Time(now.Add(time.Duration(n)*24*time.Hour + time.Hour))
+}
+
+func Test_DetectAlerts(t *testing.T) {
+	expiring := goacm.Certificate{Arn: "expiring", DomainName: "expiring.example.com", Status: string(types.CertificateStatusIssued), NotAfter: days(10)}
+	expired := goacm.Certificate{Arn: "expired", DomainName: "expired.example.com", Status: string(types.CertificateStatusExpired), NotAfter: days(-2)}
+	healthy := goacm.Certificate{Arn: "healthy", DomainName: "healthy.example.com", Status: string(types.CertificateStatusIssued), NotAfter: days(300)}
+	failed := goacm.Certificate{
+		Arn:           "failed",
+		DomainName:    "failed.example.com",
+		Status:        string(types.CertificateStatusFailed),
+		FailureReason: string(types.FailureReasonCaaError),
+	}
+	rs := goacm.RecordSet{Name: "_validation.name.example.com", Value: "_validation.value.example.com"}
+	readiness := []goacm.RenewalReadiness{
+		{
+			CertificateArn: "lost",
+			DomainName:     "lost.example.com",
+			Records: []goacm.ValidationRecordCheck{
+				{RecordSet: rs, State: goacm.ValidationRecordStateMissing},
+				{RecordSet: rs, State: goacm.ValidationRecordStateMismatch, Repaired: true},
+				{RecordSet: rs, State: goacm.ValidationRecordStateOK},
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		thresholds []int
+		expect     []notify.Alert
+	}{
+		{
+			name: "normal",
+			expect: []notify.Alert{
+				{
+					Kind:           notify.AlertKindExpiring,
+					CertificateArn: "expiring",
+					DomainName:     "expiring.example.com",
+					NotAfter:       expiring.NotAfter,
+					DaysLeft:       10,
+					Threshold:      14,
+					Message:        "Certificate of expiring.example.com expires in 10 days at 2022-01-11T01:00:00Z",
+				},
+				{
+					Kind:           notify.AlertKindExpired,
+					CertificateArn: "expired",
+					DomainName:     "expired.example.com",
+					NotAfter:       expired.NotAfter,
+					DaysLeft:       -2,
+					Message:        "Certificate of expired.example.com expired at 2021-12-30T01:00:00Z",
+				},
+				{
+					Kind:           notify.AlertKindFailed,
+					CertificateArn: "failed",
+					DomainName:     "failed.example.com",
+					Status:         string(types.CertificateStatusFailed),
+					FailureReason:  string(types.FailureReasonCaaError),
+					Message:        "Certificate of failed.example.com is FAILED (CAA_ERROR)",
+				},
+				{
+					Kind:           notify.AlertKindValidationRecordLost,
+					CertificateArn: "lost",
+					DomainName:     "lost.example.com",
+					RecordName:     "_validation.name.example.com",
+					Message:        "Record _validation.name.example.com that validates lost.example.com is MISSING, and managed renewal will fail",
+				},
+			},
+		},
+		{
+			name:       "normal: no threshold crossed",
+			thresholds: []int{7, 1},
+			expect: []notify.Alert{
+				{
+					Kind:           notify.AlertKindExpired,
+					CertificateArn: "expired",
+					DomainName:     "expired.example.com",
+					NotAfter:       expired.NotAfter,
+					DaysLeft:       -2,
+					Message:        "Certificate of expired.example.com expired at 2021-12-30T01:00:00Z",
+				},
+				{
+					Kind:           notify.AlertKindFailed,
+					CertificateArn: "failed",
+					DomainName:     "failed.example.com",
+					Status:         string(types.CertificateStatusFailed),
+					FailureReason:  string(types.FailureReasonCaaError),
+					Message:        "Certificate of failed.example.com is FAILED (CAA_ERROR)",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			cList := []goacm.Certificate{expiring, expired, healthy, failed}
+			var r []goacm.RenewalReadiness
+			if c.thresholds == nil {
+				r = readiness
+			}
+
+			alerts := notify.DetectAlerts(cList, r, func(o *notify.AlertOptions) {
+				o.Thresholds = c.thresholds
+				o.Now = now
+			})
+			assert.Equal(tt, c.expect, alerts)
+		})
+	}
+}
+
+// testNotifier is a notifier that fails for the certificate of the ARN, and records the alerts sent.
+type testNotifier struct {
+	id   string
+	fail string
+	sent *[]notify.Alert
+}
+
+func (n testNotifier) Notify(ctx context.Context, a notify.Alert) error {
+	if a.CertificateArn == n.fail {
+		return errors.New("unavailable")
+	}
+	*n.sent = append(*n.sent, a)
+	return nil
+}
+
+func (n testNotifier) ID() string {
+	return n.id
+}
+
+func Test_Notify(t *testing.T) {
+	a1 := notify.Alert{Kind: notify.AlertKindExpiring, CertificateArn: "a1", Threshold: 30, NotAfter: days(20)}
+	a2 := notify.Alert{Kind: notify.AlertKindFailed, CertificateArn: "a2", Status: string(types.CertificateStatusFailed)}
+	a1Crossed := a1
+	a1Crossed.Threshold = 14
+
+	cases := []struct {
+		name        string
+		sent        []string
+		alerts      []notify.Alert
+		failSlack   string
+		failTeams   string
+		wantErr     bool
+		expect      []notify.Alert
+		expectSlack []notify.Alert
+		expectTeams []notify.Alert
+		expectState []string
+	}{
+		{
+			name:        "normal",
+			alerts:      []notify.Alert{a1, a2},
+			expect:      []notify.Alert{a1, a2},
+			expectSlack: []notify.Alert{a1, a2},
+			expectTeams: []notify.Alert{a1, a2},
+			expectState: []string{a1.Key() + "#slack", a1.Key() + "#teams", a2.Key() + "#slack", a2.Key() + "#teams"},
+		},
+		{
+			name:        "normal: not repeated",
+			sent:        []string{a1.Key() + "#slack", a1.Key() + "#teams"},
+			alerts:      []notify.Alert{a1, a2},
+			expect:      []notify.Alert{a2},
+			expectSlack: []notify.Alert{a2},
+			expectTeams: []notify.Alert{a2},
+			expectState: []string{a1.Key() + "#slack", a1.Key() + "#teams", a2.Key() + "#slack", a2.Key() + "#teams"},
+		},
+		{
+			name:        "normal: next threshold crossed",
+			sent:        []string{a1.Key() + "#slack", a1.Key() + "#teams"},
+			alerts:      []notify.Alert{a1Crossed},
+			expect:      []notify.Alert{a1Crossed},
+			expectSlack: []notify.Alert{a1Crossed},
+			expectTeams: []notify.Alert{a1Crossed},
+			// records are removed only by Resolve
+			expectState: []string{a1.Key() + "#slack", a1.Key() + "#teams", a1Crossed.Key() + "#slack", a1Crossed.Key() + "#teams"},
+		},
+		{
+			name:        "normal: retried only for the failed notifier",
+			sent:        []string{a2.Key() + "#slack"},
+			alerts:      []notify.Alert{a2},
+			expect:      []notify.Alert{a2},
+			expectSlack: []notify.Alert{},
+			expectTeams: []notify.Alert{a2},
+			expectState: []string{a2.Key() + "#slack", a2.Key() + "#teams"},
+		},
+		{
+			name:        "error: retried in the next run",
+			alerts:      []notify.Alert{a1, a2},
+			failTeams:   "a2",
+			wantErr:     true,
+			expect:      []notify.Alert{a1, a2},
+			expectSlack: []notify.Alert{a1, a2},
+			expectTeams: []notify.Alert{a1},
+			expectState: []string{a1.Key() + "#slack", a1.Key() + "#teams", a2.Key() + "#slack"},
+		},
+		{
+			name:        "error: failed for all notifiers",
+			alerts:      []notify.Alert{a1, a2},
+			failSlack:   "a2",
+			failTeams:   "a2",
+			wantErr:     true,
+			expect:      []notify.Alert{a1},
+			expectSlack: []notify.Alert{a1},
+			expectTeams: []notify.Alert{a1},
+			expectState: []string{a1.Key() + "#slack", a1.Key() + "#teams"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			state := &notify.State{Sent: map[string]time.Time{}}
+			for _, k := range c.sent {
+				state.Sent[k] = now
+			}
+			slack := []notify.Alert{}
+			teams := []notify.Alert{}
+			notifiers := []notify.Notifier{
+				testNotifier{id: "slack", fail: c.failSlack, sent: &slack},
+				testNotifier{id: "teams", fail: c.failTeams, sent: &teams},
+			}
+
+			sent, err := notify.Notify(context.TODO(), c.alerts, notifiers, state, now)
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.Equal(tt, c.expect, sent)
+			assert.Equal(tt, c.expectSlack, slack)
+			assert.Equal(tt, c.expectTeams, teams)
+			keys := []string{}
+			for k := range state.Sent {
+				keys = append(keys, k)
+			}
+			assert.ElementsMatch(tt, c.expectState, keys)
+		})
+	}
+}
+
+func Test_State_Resolve(t *testing.T) {
+	expiring := notify.Alert{Kind: notify.AlertKindExpiring, CertificateArn: "expiring", Threshold: 30, NotAfter: days(20)}
+	renewed := notify.Alert{Kind: notify.AlertKindExpiring, CertificateArn: "renewed", Threshold: 30, NotAfter: days(20)}
+	missing := notify.Alert{Kind: notify.AlertKindFailed, CertificateArn: "missing", Status: string(types.CertificateStatusFailed)}
+	lost := notify.Alert{Kind: notify.AlertKindValidationRecordLost, CertificateArn: "renewed", RecordName: "_validation.name.example.com"}
+	listed := []goacm.Certificate{{Arn: "expiring"}, {Arn: "renewed"}}
+
+	cases := []struct {
+		name           string
+		recordsChecked bool
+		expectState    []string
+	}{
+		{
+			name:           "normal",
+			recordsChecked: true,
+			// the certificate that was not listed keeps the record
+			expectState: []string{expiring.Key() + "#slack", missing.Key() + "#slack"},
+		},
+		{
+			name:        "normal: records not checked",
+			expectState: []string{expiring.Key() + "#slack", missing.Key() + "#slack", lost.Key() + "#slack"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			state := &notify.State{Sent: map[string]time.Time{}}
+			for _, a := range []notify.Alert{expiring, renewed, missing, lost} {
+				state.Sent[a.Key()+"#slack"] = now
+			}
+
+			state.Resolve([]notify.Alert{expiring}, listed, c.recordsChecked)
+
+			keys := []string{}
+			for k := range state.Sent {
+				keys = append(keys, k)
+			}
+			assert.ElementsMatch(tt, c.expectState, keys)
+		})
+	}
+}
+
+func Test_State(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := notify.LoadState(path)
+	assert.NoError(t, err)
+	assert.Empty(t, s.Sent)
+
+	s.Sent["key"] = now
+	assert.NoError(t, s.Save(path))
+
+	loaded, err := notify.LoadState(path)
+	assert.NoError(t, err)
+	assert.Equal(t, s, loaded)
+}
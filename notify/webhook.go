@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Format is a type that represents a format of webhook payloads.
+type Format string
+
+// Formats of webhook payloads.
+const (
+	// FormatGeneric posts the alert as JSON.
+	FormatGeneric Format = "generic"
+
+	// FormatSlack posts a message of Slack incoming webhooks.
+	FormatSlack Format = "slack"
+
+	// FormatTeams posts a message card of Microsoft Teams incoming webhooks.
+	FormatTeams Format = "teams"
+)
+
+// Formats are the supported formats of webhook payloads.
+var Formats = []Format{FormatGeneric, FormatSlack, FormatTeams}
+
+// ParseFormat returns the format of the name, case-insensitively.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown webhook format: %s", s)
+}
+
+// DefaultWebhookTimeout is a default timeout of posting an alert to a webhook.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// defaultClient is the HTTP client of webhooks without Client, which does not wait for unresponsive webhooks forever.
+var defaultClient = &http.Client{Timeout: DefaultWebhookTimeout}
+
+// Webhook is a structure that represents a webhook that receives alerts.
+type Webhook struct {
+	URL    string
+	Format Format
+
+	// Client is an HTTP client to post alerts. Default is a client with DefaultWebhookTimeout.
+	Client *http.Client
+}
+
+// ID returns the format and a hash of the URL, so that the URL, which is often a secret, is not recorded in the state.
+func (w Webhook) ID() string {
+	h := sha256.Sum256([]byte(w.URL))
+	return fmt.Sprintf("%s:%s", w.Format, hex.EncodeToString(h[:8]))
+}
+
+// Notify posts the alert to the webhook. Responses other than 2xx are errors.
+func (w Webhook) Notify(ctx context.Context, a Alert) error {
+	payload, err := w.payload(a)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return withoutURL(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = defaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return withoutURL(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("webhook responded %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Returns the error without the URL of the webhook, which is often a secret.
+func withoutURL(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return fmt.Errorf("failed to %s the webhook: %w", strings.ToLower(ue.Op), ue.Err)
+	}
+	return err
+}
+
+// Returns the payload of the alert in the format of the webhook.
+func (w Webhook) payload(a Alert) ([]byte, error) {
+	text := fmt.Sprintf("[%s] %s\n%s", a.Kind, a.Message, a.CertificateArn)
+
+	switch w.Format {
+	case FormatGeneric, "":
+		return json.Marshal(a)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": text})
+	case FormatTeams:
+		return json.Marshal(map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    a.Message,
+			"themeColor": themeColor(a.Kind),
+			"title":      fmt.Sprintf("ACM certificate %s", a.Kind),
+			"text":       a.Message + "\n\n" + a.CertificateArn,
+		})
+	}
+	return nil, fmt.Errorf("unknown webhook format: %s", w.Format)
+}
+
+// Returns the color of Teams message cards; red for failures, and orange for warnings.
+func themeColor(kind AlertKind) string {
+	if kind == AlertKindExpiring {
+		return "FFA500"
+	}
+	return "FF0000"
+}
@@ -0,0 +1,125 @@
+package notify_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/michimani/goacm/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Webhook_Notify(t *testing.T) {
+	a := notify.Alert{
+		Kind:           notify.AlertKindExpiring,
+		CertificateArn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring",
+		DomainName:     "example.com",
+		DaysLeft:       10,
+		Threshold:      14,
+		Message:        "Certificate of example.com expires in 10 days",
+	}
+
+	cases := []struct {
+		name    string
+		format  notify.Format
+		status  int
+		wantErr bool
+		expect  string
+	}{
+		{
+			name:   "normal: generic",
+			format: notify.FormatGeneric,
+			status: http.StatusOK,
+			expect: `{"kind":"EXPIRING","certificateArn":"arn:aws:acm:ap-northeast-1:000000000000:certificate/expiring","domainName":"example.com","daysLeft":10,"threshold":14,"message":"Certificate of example.com expires in 10 days"}`,
+		},
+		{
+			name:   "normal: slack",
+			format: notify.FormatSlack,
+			status: http.StatusOK,
+			expect: `{"text":"[EXPIRING] Certificate of example.com expires in 10 days\narn:aws:acm:ap-northeast-1:000000000000:certificate/expiring"}`,
+		},
+		{
+			name:   "normal: teams",
+			format: notify.FormatTeams,
+			status: http.StatusAccepted,
+			expect: `{"@context":"https://schema.org/extensions","@type":"MessageCard","summary":"Certificate of example.com expires in 10 days","text":"Certificate of example.com expires in 10 days\n\narn:aws:acm:ap-northeast-1:000000000000:certificate/expiring","themeColor":"FFA500","title":"ACM certificate EXPIRING"}`,
+		},
+		{
+			name:    "error: response status",
+			format:  notify.FormatSlack,
+			status:  http.StatusNotFound,
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			var body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(tt, http.MethodPost, r.Method)
+				assert.Equal(tt, "application/json", r.Header.Get("Content-Type"))
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				w.WriteHeader(c.status)
+			}))
+			defer srv.Close()
+
+			err := notify.Webhook{URL: srv.URL, Format: c.format}.Notify(context.TODO(), a)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, body)
+		})
+	}
+}
+
+func Test_Webhook_ID(t *testing.T) {
+	slack := notify.Webhook{URL: "https://hooks.slack.com/services/xxx", Format: notify.FormatSlack}
+	other := notify.Webhook{URL: "https://hooks.slack.com/services/yyy", Format: notify.FormatSlack}
+
+	assert.Equal(t, slack.ID(), notify.Webhook{URL: slack.URL, Format: notify.FormatSlack}.ID())
+	assert.NotEqual(t, slack.ID(), other.ID())
+	assert.NotContains(t, slack.ID(), "xxx")
+}
+
+func Test_Webhook_Notify_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	w := notify.Webhook{URL: srv.URL, Format: notify.FormatSlack, Client: &http.Client{Timeout: 10 * time.Millisecond}}
+	assert.Error(t, w.Notify(context.TODO(), notify.Alert{Kind: notify.AlertKindExpired}))
+}
+
+func Test_Webhook_Notify_ErrorWithoutURL(t *testing.T) {
+	cases := []struct {
+		name string
+		url  string
+	}{
+		{
+			name: "error: connection refused",
+			url:  "http://127.0.0.1:1/services/T000/B000/SECRETTOKEN",
+		},
+		{
+			name: "error: invalid URL",
+			url:  "http://127.0.0.1:1/services/T000/B000/SECRETTOKEN\x7f",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			err := notify.Webhook{URL: c.url, Format: notify.FormatSlack}.Notify(context.TODO(), notify.Alert{Kind: notify.AlertKindExpired})
+			assert.Error(tt, err)
+			assert.NotContains(tt, err.Error(), "SECRETTOKEN")
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// defaultRoleSessionName is a session name used when assuming a role without SessionName.
+const defaultRoleSessionName = "goacm"
+
+// GoACMOptions is a structure that represents options for NewGoACM.
+type GoACMOptions struct {
+	// Profile is a name of the shared config profile. It cannot be used with Config.
+	Profile string
+
+	// Config is an existing aws.Config. If set, the default config is not loaded.
+	// It cannot be used with Profile.
+	Config *aws.Config
+
+	// ACMAssumeRole is an IAM role assumed by the ACM client.
+	// It is also used by the Route 53 client if Route53AssumeRole is not set.
+	ACMAssumeRole *AssumeRole
+
+	// Route53AssumeRole is an IAM role assumed by the Route 53 client.
+	Route53AssumeRole *AssumeRole
+
+	// ACMEndpoint is a URL that overrides the endpoint of ACM.
+	ACMEndpoint string
+
+	// Route53Endpoint is a URL that overrides the endpoint of Route 53.
+	Route53Endpoint string
+
+	// UseFIPSEndpoint makes the ACM client use the FIPS endpoint of the region.
+	UseFIPSEndpoint bool
+
+	// ACMAccountID is the ID of the account of the ACM client used in errors. If empty, it is the account of
+	// ACMAssumeRole, or the account of the credentials resolved with STS GetCallerIdentity.
+	// It is not resolved with STS if ACMEndpoint is set, because a custom endpoint is not in the account.
+	ACMAccountID string
+
+	// Route53AccountID is the ID of the account of the Route 53 client used in errors.
+	// If empty, it is the account of Route53AssumeRole, or the account of the ACM client.
+	Route53AccountID string
+}
+
+// AssumeRole is a structure that represents an IAM role to assume.
+type AssumeRole struct {
+	RoleArn     string
+	ExternalID  string
+	SessionName string
+}
+
+// WithProfile returns an option that selects the shared config profile.
+func WithProfile(profile string) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.Profile = profile
+	}
+}
+
+// WithConfig returns an option that uses an existing aws.Config.
+func WithConfig(cfg aws.Config) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.Config = &cfg
+	}
+}
+
+// WithAssumeRole returns an option that assumes the role for the ACM and Route 53 clients.
+func WithAssumeRole(role AssumeRole) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.ACMAssumeRole = &role
+	}
+}
+
+// WithRoute53AssumeRole returns an option that assumes the role only for the Route 53 client.
+func WithRoute53AssumeRole(role AssumeRole) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.Route53AssumeRole = &role
+	}
+}
+
+// WithACMEndpoint returns an option that overrides the endpoint of ACM.
+func WithACMEndpoint(url string) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.ACMEndpoint = url
+	}
+}
+
+// WithRoute53Endpoint returns an option that overrides the endpoint of Route 53.
+func WithRoute53Endpoint(url string) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.Route53Endpoint = url
+	}
+}
+
+// WithFIPSEndpoint returns an option that makes the ACM client use the FIPS endpoint.
+func WithFIPSEndpoint() func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.UseFIPSEndpoint = true
+	}
+}
+
+// WithAccountIDs returns an option that sets the IDs of the accounts of the ACM and Route 53 clients,
+// instead of resolving them.
+func WithAccountIDs(acmAccountID, route53AccountID string) func(*GoACMOptions) {
+	return func(o *GoACMOptions) {
+		o.ACMAccountID = acmAccountID
+		o.Route53AccountID = route53AccountID
+	}
+}
+
+// Returns the IDs of the accounts of the ACM and Route 53 clients.
+func resolveAccountIDs(ctx context.Context, api STSGetCallerIdentityAPI, o GoACMOptions) (string, string) {
+	acmAccountID := o.ACMAccountID
+	if acmAccountID == "" {
+		acmAccountID = roleAccountID(o.ACMAssumeRole)
+	}
+	if acmAccountID == "" && o.ACMAssumeRole == nil && o.ACMEndpoint == "" {
+		acmAccountID = callerAccountID(ctx, api)
+	}
+
+	route53AccountID := o.Route53AccountID
+	if route53AccountID == "" {
+		route53AccountID = acmAccountID
+		if o.Route53AssumeRole != nil {
+			route53AccountID = roleAccountID(o.Route53AssumeRole)
+		}
+	}
+	return acmAccountID, route53AccountID
+}
+
+// Load the base config, and resolve configs for ACM and Route 53 from it.
+func resolveConfigs(ctx context.Context, region string, o GoACMOptions) (aws.Config, aws.Config, error) {
+	if o.Config != nil && o.Profile != "" {
+		return aws.Config{}, aws.Config{}, errors.New("profile cannot be used with an existing config")
+	}
+
+	var cfg aws.Config
+	if o.Config != nil {
+		cfg = o.Config.Copy()
+		if region != "" {
+			cfg.Region = region
+		}
+	} else {
+		loadOpts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+		if o.Profile != "" {
+			loadOpts = append(loadOpts, config.WithSharedConfigProfile(o.Profile))
+		}
+
+		c, err := config.LoadDefaultConfig(ctx, loadOpts...)
+		if err != nil {
+			return aws.Config{}, aws.Config{}, err
+		}
+		cfg = c
+	}
+
+	acmCfg := cfg.Copy()
+	if o.ACMAssumeRole != nil {
+		acmCfg.Credentials = newAssumeRoleCredentials(cfg, *o.ACMAssumeRole)
+	}
+
+	route53Cfg := acmCfg.Copy()
+	if o.Route53AssumeRole != nil {
+		route53Cfg.Credentials = newAssumeRoleCredentials(cfg, *o.Route53AssumeRole)
+	}
+
+	return acmCfg, route53Cfg, nil
+}
+
+// Returns a credentials provider that assumes the role using the credentials of cfg.
+func newAssumeRoleCredentials(cfg aws.Config, role AssumeRole) aws.CredentialsProvider {
+	p := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = defaultRoleSessionName
+		if role.SessionName != "" {
+			o.RoleSessionName = role.SessionName
+		}
+		if role.ExternalID != "" {
+			o.ExternalID = aws.String(role.ExternalID)
+		}
+	})
+
+	return aws.NewCredentialsCache(p)
+}
+
+// Returns options for the ACM client.
+func acmClientOptions(o GoACMOptions) []func(*acm.Options) {
+	var optFns []func(*acm.Options)
+	if o.ACMEndpoint != "" {
+		optFns = append(optFns, func(ao *acm.Options) {
+			ao.BaseEndpoint = aws.String(o.ACMEndpoint)
+		})
+	}
+	if o.UseFIPSEndpoint {
+		optFns = append(optFns, func(ao *acm.Options) {
+			ao.EndpointOptions.UseFIPSEndpoint = aws.FIPSEndpointStateEnabled
+		})
+	}
+
+	return optFns
+}
+
+// Returns options for the Route 53 client.
+func route53ClientOptions(o GoACMOptions) []func(*route53.Options) {
+	var optFns []func(*route53.Options)
+	if o.Route53Endpoint != "" {
+		optFns = append(optFns, func(ro *route53.Options) {
+			ro.BaseEndpoint = aws.String(o.Route53Endpoint)
+		})
+	}
+
+	return optFns
+}
@@ -0,0 +1,212 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewGoACM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(`{"CertificateSummaryList":[{"CertificateArn":"arn:aws:acm:us-east-1:000000000000:certificate/this-is-a-sample-arn","DomainName":"test.example.com"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      "ap-northeast-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}
+
+	cases := []struct {
+		name         string
+		region       string
+		optFns       []func(*goacm.GoACMOptions)
+		expectRegion string
+	}{
+		{
+			name:         "normal: use region of config",
+			region:       "",
+			optFns:       []func(*goacm.GoACMOptions){goacm.WithConfig(cfg), goacm.WithACMEndpoint(server.URL)},
+			expectRegion: "ap-northeast-1",
+		},
+		{
+			name:         "normal: override region of config",
+			region:       "us-east-1",
+			optFns:       []func(*goacm.GoACMOptions){goacm.WithConfig(cfg), goacm.WithACMEndpoint(server.URL)},
+			expectRegion: "us-east-1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			g, err := goacm.NewGoACM(context.TODO(), c.region, c.optFns...)
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expectRegion, g.Region)
+
+			s, err := goacm.ListCertificateSummaries(context.TODO(), g.ACMClient)
+			assert.NoError(tt, err)
+			assert.Len(tt, s, 1)
+			assert.Equal(tt, "test.example.com", aws.ToString(s[0].DomainName))
+		})
+	}
+}
+
+func Test_resolveConfigs(t *testing.T) {
+	// a pointer to compare providers, because static providers are not comparable
+	var static aws.CredentialsProvider = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""))
+	cfg := aws.Config{
+		Region:      "ap-northeast-1",
+		Credentials: static,
+	}
+
+	cases := []struct {
+		name               string
+		options            goacm.GoACMOptions
+		expectACMStatic    bool
+		expectSharedConfig bool
+		wantErr            bool
+	}{
+		{
+			name:               "normal: no role",
+			options:            goacm.GoACMOptions{Config: &cfg},
+			expectACMStatic:    true,
+			expectSharedConfig: true,
+		},
+		{
+			name: "normal: assume role for both",
+			options: goacm.GoACMOptions{
+				Config:        &cfg,
+				ACMAssumeRole: &goacm.AssumeRole{RoleArn: "arn:aws:iam::111111111111:role/acm"},
+			},
+			expectACMStatic:    false,
+			expectSharedConfig: true,
+		},
+		{
+			name: "normal: assume another role for Route 53",
+			options: goacm.GoACMOptions{
+				Config:            &cfg,
+				Route53AssumeRole: &goacm.AssumeRole{RoleArn: "arn:aws:iam::222222222222:role/route53", ExternalID: "external-id"},
+			},
+			expectACMStatic:    true,
+			expectSharedConfig: false,
+		},
+		{
+			name: "error: profile with config",
+			options: goacm.GoACMOptions{
+				Config:  &cfg,
+				Profile: "workload",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			acmCfg, route53Cfg, err := goacm.ExportedResolveConfigs(context.TODO(), "", c.options)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, "ap-northeast-1", acmCfg.Region)
+			assert.Equal(tt, "ap-northeast-1", route53Cfg.Region)
+			assert.Equal(tt, c.expectACMStatic, acmCfg.Credentials == static)
+			assert.Equal(tt, c.expectSharedConfig, acmCfg.Credentials == route53Cfg.Credentials)
+		})
+	}
+}
+
+// mockGetCallerIdentity is an STS client that returns the account, or the error if set.
+type mockGetCallerIdentity struct {
+	account string
+	err     error
+	called  *int
+}
+
+func (m mockGetCallerIdentity) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	*m.called++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &sts.GetCallerIdentityOutput{Account: aws.String(m.account)}, nil
+}
+
+func Test_resolveAccountIDs(t *testing.T) {
+	acmRole := &goacm.AssumeRole{RoleArn: "arn:aws:iam::111111111111:role/acm"}
+	route53Role := &goacm.AssumeRole{RoleArn: "arn:aws:iam::222222222222:role/route53"}
+
+	cases := []struct {
+		name          string
+		options       goacm.GoACMOptions
+		stsErr        error
+		expectACM     string
+		expectRoute53 string
+		expectCalled  int
+	}{
+		{
+			name:          "normal: account of the credentials",
+			options:       goacm.GoACMOptions{},
+			expectACM:     "000000000000",
+			expectRoute53: "000000000000",
+			expectCalled:  1,
+		},
+		{
+			name:          "normal: accounts of the roles",
+			options:       goacm.GoACMOptions{ACMAssumeRole: acmRole, Route53AssumeRole: route53Role},
+			expectACM:     "111111111111",
+			expectRoute53: "222222222222",
+			expectCalled:  0,
+		},
+		{
+			name:          "normal: role only for Route 53",
+			options:       goacm.GoACMOptions{Route53AssumeRole: route53Role},
+			expectACM:     "000000000000",
+			expectRoute53: "222222222222",
+			expectCalled:  1,
+		},
+		{
+			name:          "normal: accounts set in the options",
+			options:       goacm.GoACMOptions{ACMAccountID: "333333333333", Route53AccountID: "444444444444"},
+			expectACM:     "333333333333",
+			expectRoute53: "444444444444",
+			expectCalled:  0,
+		},
+		{
+			name:          "normal: not resolved with a custom endpoint",
+			options:       goacm.GoACMOptions{ACMEndpoint: "http://localhost:4566"},
+			expectACM:     "",
+			expectRoute53: "",
+			expectCalled:  0,
+		},
+		{
+			name:          "normal: unknown if STS fails",
+			options:       goacm.GoACMOptions{},
+			stsErr:        errors.New("access denied"),
+			expectACM:     "",
+			expectRoute53: "",
+			expectCalled:  1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			called := 0
+			api := mockGetCallerIdentity{account: "000000000000", err: c.stsErr, called: &called}
+
+			acmAccountID, route53AccountID := goacm.ExportedResolveAccountIDs(context.TODO(), api, c.options)
+			assert.Equal(tt, c.expectACM, acmAccountID)
+			assert.Equal(tt, c.expectRoute53, route53AccountID)
+			assert.Equal(tt, c.expectCalled, called)
+		})
+	}
+}
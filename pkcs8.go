@@ -0,0 +1,179 @@
+package goacm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"hash"
+)
+
+// errDecryptPrivateKey is returned for any failure in decrypting private keys,
+// so that errors tell nothing about the key nor the passphrase.
+var errDecryptPrivateKey = errors.New("cannot decrypt the private key: the passphrase is incorrect or the encryption is not supported")
+
+// maxPBKDF2Iterations is the maximum iteration count of PBKDF2 to derive keys,
+// so that keys with a huge count do not make decrypting take forever.
+const maxPBKDF2Iterations = 1000000
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3   = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// Decrypt the PEM encoded private key that ACM encrypted with the passphrase by PBES2 of PKCS#5,
+// and return the key with its PKCS#8 DER.
+func decryptPrivateKey(data, passphrase []byte) (interface{}, []byte, error) {
+	b, _ := pem.Decode(data)
+	if b == nil || b.Type != "ENCRYPTED PRIVATE KEY" {
+		return nil, nil, errors.New("private key is not a PEM encoded encrypted private key")
+	}
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(b.Bytes, &info); err != nil || !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, nil, errDecryptPrivateKey
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, nil, errDecryptPrivateKey
+	}
+
+	block, keyLen, err := pbes2Cipher(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, errDecryptPrivateKey
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, errDecryptPrivateKey
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, nil, errDecryptPrivateKey
+	}
+	if kdf.IterationCount < 1 || kdf.IterationCount > maxPBKDF2Iterations {
+		return nil, nil, errDecryptPrivateKey
+	}
+	prf, err := pbkdf2PRF(kdf.PRF.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := pbkdf2.Key(prf, string(passphrase), kdf.Salt, kdf.IterationCount, keyLen)
+	if err != nil {
+		return nil, nil, errDecryptPrivateKey
+	}
+	defer zero(key)
+	c, err := block(key)
+	if err != nil || len(iv) != c.BlockSize() || len(info.EncryptedData)%c.BlockSize() != 0 {
+		return nil, nil, errDecryptPrivateKey
+	}
+
+	plain := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(c, iv).CryptBlocks(plain, info.EncryptedData)
+	der, ok := unpad(plain, c.BlockSize())
+	if !ok {
+		zero(plain)
+		return nil, nil, errDecryptPrivateKey
+	}
+
+	pk, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		zero(plain)
+		return nil, nil, errDecryptPrivateKey
+	}
+
+	return pk, der, nil
+}
+
+// Return the block cipher and its key length of the encryption scheme.
+func pbes2Cipher(oid asn1.ObjectIdentifier) (func([]byte) (cipher.Block, error), int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, nil
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, nil
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, nil
+	case oid.Equal(oidDESEDE3):
+		return des.NewTripleDESCipher, 24, nil
+	}
+	return nil, 0, errDecryptPrivateKey
+}
+
+// Return the hash of the pseudorandom function of PBKDF2. The default is HMAC with SHA-1.
+func pbkdf2PRF(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case len(oid) == 0, oid.Equal(oidHMACWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidHMACWithSHA512):
+		return sha512.New, nil
+	}
+	return nil, errDecryptPrivateKey
+}
+
+// Remove the PKCS#7 padding, or return false if the padding is broken.
+func unpad(b []byte, blockSize int) ([]byte, bool) {
+	if len(b) == 0 {
+		return nil, false
+	}
+	n := int(b[len(b)-1])
+	if n == 0 || n > blockSize || n > len(b) {
+		return nil, false
+	}
+	pad := make([]byte, n)
+	for i := range pad {
+		pad[i] = byte(n)
+	}
+	if !bytes.Equal(b[len(b)-n:], pad) {
+		return nil, false
+	}
+	return b[:len(b)-n], true
+}
+
+// Overwrite the secret with zeros.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,221 @@
+package goacm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// UnknownRecordValue is a value of records in plans that is known only after requesting the certificate.
+const UnknownRecordValue = "(known after request)"
+
+// PlanAction is a type that represents a change in a plan.
+type PlanAction string
+
+// Changes in plans.
+const (
+	PlanActionRequest PlanAction = "REQUEST"
+	PlanActionCreate  PlanAction = "CREATE"
+	PlanActionDelete  PlanAction = "DELETE"
+)
+
+// PlannedChange is a structure that represents a change to ACM or Route 53 that an operation will make.
+type PlannedChange struct {
+	// Service is ServiceACM or ServiceRoute53.
+	Service string     `json:"service" yaml:"service"`
+	Action  PlanAction `json:"action" yaml:"action"`
+
+	// Region and CertificateArn are set for changes to ACM. The ARN is empty for requests.
+	Region         string `json:"region,omitempty" yaml:"region,omitempty"`
+	CertificateArn string `json:"certificateArn,omitempty" yaml:"certificateArn,omitempty"`
+
+	// DomainName is the domain name of the certificate.
+	DomainName              string   `json:"domainName" yaml:"domainName"`
+	SubjectAlternativeNames []string `json:"subjectAlternativeNames,omitempty" yaml:"subjectAlternativeNames,omitempty"`
+
+	// KeyAlgorithm is the key algorithm of certificates to request. Empty means the default of ACM.
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty" yaml:"keyAlgorithm,omitempty"`
+
+	// TransparencyLogging is the transparency logging preference of certificates to request.
+	TransparencyLogging string `json:"transparencyLogging,omitempty" yaml:"transparencyLogging,omitempty"`
+
+	// HostedZoneID and RecordSet are set for changes to Route 53.
+	// Names and values of records to create are UnknownRecordValue until the certificate is requested.
+	HostedZoneID string    `json:"hostedZoneId,omitempty" yaml:"hostedZoneId,omitempty"`
+	RecordSet    RecordSet `json:"recordSet,omitempty" yaml:"recordSet,omitempty"`
+}
+
+// Plan is a structure that represents changes an operation will make.
+type Plan struct {
+	Changes []PlannedChange `json:"changes" yaml:"changes"`
+}
+
+// PlanIssueCertificate returns the changes IssueCertificate will make, calling only read APIs.
+// The records that validate the domains are generated by ACM after requesting the certificate,
+// so their names and values are UnknownRecordValue.
+func PlanIssueCertificate(ctx context.Context, rAPI Route53API, method, targetDomain, hostedDomain string, optFns ...func(*IssueCertificateOptions)) (Plan, error) {
+	o := IssueCertificateOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if err := ValidateKeyAlgorithm(o.KeyAlgorithm); err != nil {
+		return Plan{}, err
+	}
+	if err := ValidateTransparencyLogging(o.TransparencyLogging); err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{Changes: []PlannedChange{
+		{
+			Service:                 ServiceACM,
+			Action:                  PlanActionRequest,
+			DomainName:              targetDomain,
+			SubjectAlternativeNames: o.SubjectAlternativeNames,
+			KeyAlgorithm:            o.KeyAlgorithm,
+			TransparencyLogging:     o.TransparencyLogging,
+		},
+	}}
+
+	if method == string(acmTypes.ValidationMethodEmail) {
+		return plan, nil
+	}
+
+	hzID, err := getPublicHostedZoneIDByDomainName(ctx, rAPI, hostedDomain)
+	if err != nil {
+		return Plan{}, err
+	}
+	if hzID == "" {
+		return Plan{}, fmt.Errorf("Cannot get public hosted zone ID of %s in %s", hostedDomain, apiLabel(rAPI, ServiceRoute53))
+	}
+
+	// domains such as "example.com" and "*.example.com" share a record
+	planned := map[string]bool{}
+	for _, d := range append([]string{targetDomain}, o.SubjectAlternativeNames...) {
+		if planned[validationDomain(d)] {
+			continue
+		}
+		planned[validationDomain(d)] = true
+
+		plan.Changes = append(plan.Changes, PlannedChange{
+			Service:      ServiceRoute53,
+			Action:       PlanActionCreate,
+			DomainName:   validationDomain(d),
+			HostedZoneID: hzID,
+			RecordSet: RecordSet{
+				HostedDomainName: hostedDomain,
+				Name:             UnknownRecordValue,
+				Value:            UnknownRecordValue,
+				Type:             string(route53Types.RRTypeCname),
+			},
+		})
+	}
+
+	return plan, nil
+}
+
+// PlanDeleteCertificate returns the changes DeleteCertificate will make, calling only read APIs.
+// Records that other certificates use and records that do not exist are not changed.
+// It returns *CertificateInUseError if the certificate is in use and Force is not set.
+func PlanDeleteCertificate(ctx context.Context, aAPI ACMAPI, rAPI Route53API, arn string, optFns ...func(*DeleteCertificateOptions)) (Plan, error) {
+	o := DeleteCertificateOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	in := acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+	out, err := aAPI.DescribeCertificate(ctx, &in)
+	if err != nil {
+		return Plan{}, err
+	}
+	c := toCertificate(arn, out.Certificate)
+	if !o.Force {
+		if err := inUseError(arn, c.InUseBy); err != nil {
+			return Plan{}, err
+		}
+	}
+
+	// Only the records that exist are deleted, so other certificates are checked only if any exists.
+	type existingRecordSet struct {
+		hostedZoneID string
+		recordSet    RecordSet
+	}
+	existing := []existingRecordSet{}
+	for _, rs := range validationRecordSets(out.Certificate) {
+		hzID, err := getPublicHostedZoneIDByDomainName(ctx, rAPI, rs.HostedDomainName)
+		if err != nil {
+			return Plan{}, err
+		}
+		if hzID == "" {
+			return Plan{}, fmt.Errorf("Cannot get hosted zone ID of %s in %s", rs.HostedDomainName, apiLabel(rAPI, ServiceRoute53))
+		}
+
+		exists, err := validationRecordExists(ctx, rAPI, hzID, rs.Name, rs.Value)
+		if err != nil {
+			return Plan{}, err
+		}
+		if exists {
+			existing = append(existing, existingRecordSet{hostedZoneID: hzID, recordSet: rs})
+		}
+	}
+
+	used := map[RecordSet]bool{}
+	if len(existing) > 0 {
+		used, err = usedValidationRecords(ctx, o.usageAPIs(aAPI, arn), map[string]bool{arn: true}, certificateDomains(out.Certificate))
+		if err != nil {
+			return Plan{}, err
+		}
+	}
+
+	plan := Plan{Changes: []PlannedChange{}}
+	for _, e := range existing {
+		if used[e.recordSet] {
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, PlannedChange{
+			Service:      ServiceRoute53,
+			Action:       PlanActionDelete,
+			DomainName:   c.DomainName,
+			HostedZoneID: e.hostedZoneID,
+			RecordSet:    e.recordSet,
+		})
+	}
+
+	plan.Changes = append(plan.Changes, PlannedChange{
+		Service:                 ServiceACM,
+		Action:                  PlanActionDelete,
+		Region:                  arnRegion(arn),
+		CertificateArn:          arn,
+		DomainName:              c.DomainName,
+		SubjectAlternativeNames: c.SubjectAlternativeNames,
+	})
+
+	return plan, nil
+}
+
+// PlanIssueCertificate returns the changes IssueCertificate will make.
+func (g *GoACM) PlanIssueCertificate(ctx context.Context, method, targetDomain, hostedDomain string, optFns ...func(*IssueCertificateOptions)) (Plan, error) {
+	plan, err := PlanIssueCertificate(ctx, g.Route53API(), method, targetDomain, hostedDomain, optFns...)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	for i := range plan.Changes {
+		if plan.Changes[i].Service == ServiceACM {
+			plan.Changes[i].Region = g.Region
+		}
+	}
+	return plan, nil
+}
+
+// PlanDeleteCertificate returns the changes DeleteCertificate will make.
+func (g *GoACM) PlanDeleteCertificate(ctx context.Context, arn string, optFns ...func(*DeleteCertificateOptions)) (Plan, error) {
+	return PlanDeleteCertificate(ctx, g.ACMAPI(), g.Route53API(), arn, optFns...)
+}
@@ -0,0 +1,211 @@
+package goacm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns mock clients that fail the test if a mutating API is called.
+func readOnlyMocks(t *testing.T, ap []goacm.MockACMParams, rp []goacm.MockRoute53Params) (goacm.MockACMAPI, goacm.MockRoute53API) {
+	aAPI := goacm.NewMockACMAPI(ap)
+	aAPI.RequestCertificateAPI = func(ctx context.Context, params *acm.RequestCertificateInput, optFns ...func(*acm.Options)) (*acm.RequestCertificateOutput, error) {
+		t.Error("RequestCertificate must not be called")
+		return &acm.RequestCertificateOutput{}, nil
+	}
+	aAPI.DeleteCertificateAPI = func(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+		t.Error("DeleteCertificate must not be called")
+		return &acm.DeleteCertificateOutput{}, nil
+	}
+
+	rAPI := goacm.NewMockRoute53API(rp)
+	rAPI.ChangeResourceRecordSetsAPI = func(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+		t.Error("ChangeResourceRecordSets must not be called")
+		return &route53.ChangeResourceRecordSetsOutput{}, nil
+	}
+
+	return aAPI, rAPI
+}
+
+func Test_PlanIssueCertificate(t *testing.T) {
+	rp := []goacm.MockRoute53Params{
+		{
+			RecordSet: goacm.RecordSet{HostedDomainName: "example.com"},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		method       string
+		hostedDomain string
+		sans         []string
+		wantErr      bool
+		expect       goacm.Plan
+	}{
+		{
+			name:         "normal",
+			method:       string(types.ValidationMethodDns),
+			hostedDomain: "example.com",
+			sans:         []string{"*.example.com", "www.example.com"},
+			expect: goacm.Plan{Changes: []goacm.PlannedChange{
+				{
+					Service:                 goacm.ServiceACM,
+					Action:                  goacm.PlanActionRequest,
+					DomainName:              "example.com",
+					SubjectAlternativeNames: []string{"*.example.com", "www.example.com"},
+				},
+				{
+					Service:      goacm.ServiceRoute53,
+					Action:       goacm.PlanActionCreate,
+					DomainName:   "example.com",
+					HostedZoneID: "example-com",
+					RecordSet: goacm.RecordSet{
+						HostedDomainName: "example.com",
+						Name:             goacm.UnknownRecordValue,
+						Value:            goacm.UnknownRecordValue,
+						Type:             string(route53Types.RRTypeCname),
+					},
+				},
+				{
+					Service:      goacm.ServiceRoute53,
+					Action:       goacm.PlanActionCreate,
+					DomainName:   "www.example.com",
+					HostedZoneID: "example-com",
+					RecordSet: goacm.RecordSet{
+						HostedDomainName: "example.com",
+						Name:             goacm.UnknownRecordValue,
+						Value:            goacm.UnknownRecordValue,
+						Type:             string(route53Types.RRTypeCname),
+					},
+				},
+			}},
+		},
+		{
+			name:         "normal: email",
+			method:       string(types.ValidationMethodEmail),
+			hostedDomain: "example.com",
+			expect: goacm.Plan{Changes: []goacm.PlannedChange{
+				{
+					Service:    goacm.ServiceACM,
+					Action:     goacm.PlanActionRequest,
+					DomainName: "example.com",
+				},
+			}},
+		},
+		{
+			name:         "error: hosted zone not found",
+			method:       string(types.ValidationMethodDns),
+			hostedDomain: "not-exists.example.com",
+			wantErr:      true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			_, rAPI := readOnlyMocks(tt, nil, rp)
+			plan, err := goacm.PlanIssueCertificate(context.TODO(), rAPI, c.method, "example.com", c.hostedDomain, func(o *goacm.IssueCertificateOptions) {
+				o.SubjectAlternativeNames = c.sans
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, plan)
+		})
+	}
+}
+
+func Test_PlanDeleteCertificate(t *testing.T) {
+	rs := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.test.example.com",
+		Value:            "_validation.value.test.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn",
+				DomainName:          "test.example.com",
+				Status:              string(types.CertificateStatusIssued),
+				Type:                string(types.CertificateTypeAmazonIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rs,
+			},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		route53Params []goacm.MockRoute53Params
+		arn           string
+		wantErr       bool
+		expect        goacm.Plan
+	}{
+		{
+			name:          "normal",
+			route53Params: []goacm.MockRoute53Params{{RecordSet: rs}},
+			arn:           ap[0].Certificate.Arn,
+			expect: goacm.Plan{Changes: []goacm.PlannedChange{
+				{
+					Service:      goacm.ServiceRoute53,
+					Action:       goacm.PlanActionDelete,
+					DomainName:   "test.example.com",
+					HostedZoneID: "example-com",
+					RecordSet:    rs,
+				},
+				{
+					Service:        goacm.ServiceACM,
+					Action:         goacm.PlanActionDelete,
+					Region:         "ap-northeast-1",
+					CertificateArn: ap[0].Certificate.Arn,
+					DomainName:     "test.example.com",
+				},
+			}},
+		},
+		{
+			name: "normal: record already deleted",
+			route53Params: []goacm.MockRoute53Params{
+				{RecordSet: goacm.RecordSet{HostedDomainName: "example.com"}},
+			},
+			arn: ap[0].Certificate.Arn,
+			expect: goacm.Plan{Changes: []goacm.PlannedChange{
+				{
+					Service:        goacm.ServiceACM,
+					Action:         goacm.PlanActionDelete,
+					Region:         "ap-northeast-1",
+					CertificateArn: ap[0].Certificate.Arn,
+					DomainName:     "test.example.com",
+				},
+			}},
+		},
+		{
+			name:          "error: certificate not found",
+			route53Params: []goacm.MockRoute53Params{{RecordSet: rs}},
+			arn:           "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found-arn",
+			wantErr:       true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			aAPI, rAPI := readOnlyMocks(tt, ap, c.route53Params)
+			plan, err := goacm.PlanDeleteCertificate(context.TODO(), aAPI, rAPI, c.arn)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, plan)
+		})
+	}
+}
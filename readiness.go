@@ -0,0 +1,219 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// ValidationRecordState is a type that represents a state of a record that validates a domain.
+type ValidationRecordState string
+
+// States of validation records.
+const (
+	// ValidationRecordStateOK means the record exists with the expected value.
+	ValidationRecordStateOK ValidationRecordState = "OK"
+
+	// ValidationRecordStateMissing means the record does not exist.
+	ValidationRecordStateMissing ValidationRecordState = "MISSING"
+
+	// ValidationRecordStateMismatch means the record exists with another value.
+	ValidationRecordStateMismatch ValidationRecordState = "MISMATCH"
+
+	// ValidationRecordStateNoHostedZone means no public hosted zone contains the record.
+	ValidationRecordStateNoHostedZone ValidationRecordState = "NO_HOSTED_ZONE"
+)
+
+// ValidationRecordCheck is a structure that represents a result of checking a record that validates a domain.
+type ValidationRecordCheck struct {
+	// RecordSet is the record that ACM expects.
+	RecordSet    RecordSet             `json:"recordSet" yaml:"recordSet"`
+	HostedZoneID string                `json:"hostedZoneId,omitempty" yaml:"hostedZoneId,omitempty"`
+	State        ValidationRecordState `json:"state" yaml:"state"`
+
+	// ActualValue is the value of the record if the state is MISMATCH.
+	ActualValue string `json:"actualValue,omitempty" yaml:"actualValue,omitempty"`
+
+	// Repaired is true if the record was created or updated to the expected value.
+	Repaired bool `json:"repaired,omitempty" yaml:"repaired,omitempty"`
+}
+
+// RenewalReadiness is a structure that represents whether a certificate is ready for the managed renewal.
+type RenewalReadiness struct {
+	CertificateArn string `json:"certificateArn" yaml:"certificateArn"`
+	DomainName     string `json:"domainName" yaml:"domainName"`
+
+	// Ready is true if all records that validate the domains exist with the expected values.
+	Ready   bool                    `json:"ready" yaml:"ready"`
+	Records []ValidationRecordCheck `json:"records" yaml:"records"`
+
+	// Error is a message of the error if the check failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// RenewalReadinessOptions is a structure that represents options for CheckRenewalReadiness.
+type RenewalReadinessOptions struct {
+	// CertificateArns are the certificates to check. Default is all certificates validated by DNS.
+	CertificateArns []string
+
+	// Repair creates missing records and updates records with other values.
+	// Records in no public hosted zone cannot be repaired.
+	Repair bool
+}
+
+// CheckRenewalReadiness checks that the records validating the domains of the certificates validated by DNS
+// exist in the public hosted zones with the values ACM expects, because managed renewal fails silently without them.
+// The zone of a record is the most specific public hosted zone that contains the name of the record.
+// It returns a report of each certificate, and an error that joins the errors of the certificates.
+func CheckRenewalReadiness(ctx context.Context, aAPI ACMAPI, rAPI Route53API, optFns ...func(*RenewalReadinessOptions)) ([]RenewalReadiness, error) {
+	o := RenewalReadinessOptions{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	arns := o.CertificateArns
+	if arns == nil {
+		summaries, err := ListCertificateSummaries(ctx, aAPI)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range summaries {
+			arns = append(arns, aws.ToString(s.CertificateArn))
+		}
+	}
+
+	zones := map[string]string{}
+	report := []RenewalReadiness{}
+	msgs := []string{}
+	for _, arn := range arns {
+		out, err := aAPI.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(arn)})
+		if err != nil {
+			report = append(report, RenewalReadiness{CertificateArn: arn, Error: err.Error()})
+			msgs = append(msgs, fmt.Sprintf("%s: %v", arn, err))
+			continue
+		}
+
+		d := out.Certificate
+		rsList := validationRecordSets(d)
+		if len(rsList) == 0 {
+			// certificates validated by email, imported or private have no records to check
+			if o.CertificateArns == nil {
+				continue
+			}
+			err := fmt.Errorf("certificate is not validated by DNS: %s", arn)
+			report = append(report, RenewalReadiness{CertificateArn: arn, DomainName: aws.ToString(d.DomainName), Error: err.Error()})
+			msgs = append(msgs, err.Error())
+			continue
+		}
+
+		r := checkValidationRecords(ctx, rAPI, arn, aws.ToString(d.DomainName), rsList, zones, o.Repair)
+		if r.Error != "" {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", arn, r.Error))
+		}
+		report = append(report, r)
+	}
+
+	if len(msgs) > 0 {
+		return report, errors.New(strings.Join(msgs, "; "))
+	}
+
+	return report, nil
+}
+
+// Check the records of the certificate, and repair them if repair is true.
+// zones caches the IDs of the hosted zones by the record names.
+func checkValidationRecords(ctx context.Context, rAPI Route53API, arn, domainName string, rsList []RecordSet, zones map[string]string, repair bool) RenewalReadiness {
+	r := RenewalReadiness{
+		CertificateArn: arn,
+		DomainName:     domainName,
+		Ready:          true,
+	}
+
+	for _, rs := range rsList {
+		check := ValidationRecordCheck{RecordSet: rs}
+
+		hzID, ok := zones[rs.Name]
+		if !ok {
+			hd, err := FindPublicHostedDomainName(ctx, rAPI, strings.TrimSuffix(rs.Name, "."))
+			if err == nil && hd != "" {
+				hzID, err = getPublicHostedZoneIDByDomainName(ctx, rAPI, hd)
+			}
+			if err != nil {
+				r.Ready = false
+				r.Error = err.Error()
+				return r
+			}
+			zones[rs.Name] = hzID
+		}
+		check.HostedZoneID = hzID
+
+		if hzID == "" {
+			check.State = ValidationRecordStateNoHostedZone
+		} else {
+			actual, err := cnameValue(ctx, rAPI, hzID, rs.Name)
+			if err != nil {
+				r.Ready = false
+				r.Error = err.Error()
+				return r
+			}
+
+			switch actual {
+			case rs.Value:
+				check.State = ValidationRecordStateOK
+			case "":
+				check.State = ValidationRecordStateMissing
+			default:
+				check.State = ValidationRecordStateMismatch
+				check.ActualValue = actual
+			}
+		}
+
+		if repair && (check.State == ValidationRecordStateMissing || check.State == ValidationRecordStateMismatch) {
+			if err := changeValidationRecord(ctx, rAPI, hzID, route53Types.ChangeActionUpsert, rs.Name, rs.Value); err != nil {
+				r.Error = err.Error()
+			} else {
+				check.Repaired = true
+			}
+		}
+
+		if check.State != ValidationRecordStateOK && !check.Repaired {
+			r.Ready = false
+		}
+		r.Records = append(r.Records, check)
+	}
+
+	return r
+}
+
+// Return the value of the CNAME record in the hosted zone, or "" if it does not exist.
+func cnameValue(ctx context.Context, rAPI Route53ListResourceRecordSetsAPI, hzID, name string) (string, error) {
+	in := route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hzID),
+		StartRecordName: aws.String(name),
+		StartRecordType: route53Types.RRTypeCname,
+		MaxItems:        aws.Int32(1),
+	}
+	out, err := rAPI.ListResourceRecordSets(ctx, &in)
+	if err != nil {
+		return "", err
+	}
+
+	for _, rrs := range out.ResourceRecordSets {
+		if aws.ToString(rrs.Name) != name || rrs.Type != route53Types.RRTypeCname || len(rrs.ResourceRecords) == 0 {
+			continue
+		}
+		return aws.ToString(rrs.ResourceRecords[0].Value), nil
+	}
+	return "", nil
+}
+
+// CheckRenewalReadiness checks the records of the certificates with the ACM and Route 53 clients.
+func (g *GoACM) CheckRenewalReadiness(ctx context.Context, optFns ...func(*RenewalReadinessOptions)) ([]RenewalReadiness, error) {
+	return CheckRenewalReadiness(ctx, g.ACMAPI(), g.Route53API(), optFns...)
+}
@@ -0,0 +1,203 @@
+package goacm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckRenewalReadiness(t *testing.T) {
+	rsOK := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.ok.example.com",
+		Value:            "_validation.value.ok.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	rsMissing := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.missing.example.com",
+		Value:            "_validation.value.missing.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	rsNoZone := goacm.RecordSet{
+		HostedDomainName: "example.net",
+		Name:             "_validation.name.example.net",
+		Value:            "_validation.value.example.net",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:ap-northeast-1:000000000000:certificate/ok",
+				DomainName:          "ok.example.com",
+				Status:              string(types.CertificateStatusIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rsOK,
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:ap-northeast-1:000000000000:certificate/missing",
+				DomainName:          "missing.example.com",
+				Status:              string(types.CertificateStatusIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rsMissing,
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:ap-northeast-1:000000000000:certificate/no-zone",
+				DomainName:          "example.net",
+				Status:              string(types.CertificateStatusIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rsNoZone,
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:              "arn:aws:acm:ap-northeast-1:000000000000:certificate/email",
+				DomainName:       "email.example.com",
+				Status:           string(types.CertificateStatusIssued),
+				ValidationMethod: string(types.ValidationMethodEmail),
+			},
+		},
+	}
+	rp := []goacm.MockRoute53Params{
+		{
+			RecordSet:    rsOK,
+			ChangeAction: route53Types.ChangeActionDelete,
+		},
+		{
+			RecordSet:    rsMissing,
+			ChangeAction: route53Types.ChangeActionUpsert,
+		},
+	}
+
+	ok := goacm.RenewalReadiness{
+		CertificateArn: ap[0].Certificate.Arn,
+		DomainName:     "ok.example.com",
+		Ready:          true,
+		Records:        []goacm.ValidationRecordCheck{{RecordSet: rsOK, HostedZoneID: "example-com", State: goacm.ValidationRecordStateOK}},
+	}
+	noZone := goacm.RenewalReadiness{
+		CertificateArn: ap[2].Certificate.Arn,
+		DomainName:     "example.net",
+		Records:        []goacm.ValidationRecordCheck{{RecordSet: rsNoZone, State: goacm.ValidationRecordStateNoHostedZone}},
+	}
+
+	cases := []struct {
+		name        string
+		arns        []string
+		repair      bool
+		actualValue string
+		wantErr     bool
+		expect      []goacm.RenewalReadiness
+	}{
+		{
+			name: "normal",
+			expect: []goacm.RenewalReadiness{
+				ok,
+				{
+					CertificateArn: ap[1].Certificate.Arn,
+					DomainName:     "missing.example.com",
+					Records:        []goacm.ValidationRecordCheck{{RecordSet: rsMissing, HostedZoneID: "example-com", State: goacm.ValidationRecordStateMissing}},
+				},
+				noZone,
+			},
+		},
+		{
+			name:   "normal: repair missing record",
+			repair: true,
+			expect: []goacm.RenewalReadiness{
+				ok,
+				{
+					CertificateArn: ap[1].Certificate.Arn,
+					DomainName:     "missing.example.com",
+					Ready:          true,
+					Records:        []goacm.ValidationRecordCheck{{RecordSet: rsMissing, HostedZoneID: "example-com", State: goacm.ValidationRecordStateMissing, Repaired: true}},
+				},
+				noZone,
+			},
+		},
+		{
+			name:        "normal: repair record with another value",
+			arns:        []string{ap[1].Certificate.Arn},
+			repair:      true,
+			actualValue: "_old.value.missing.example.com",
+			expect: []goacm.RenewalReadiness{
+				{
+					CertificateArn: ap[1].Certificate.Arn,
+					DomainName:     "missing.example.com",
+					Ready:          true,
+					Records: []goacm.ValidationRecordCheck{
+						{
+							RecordSet:    rsMissing,
+							HostedZoneID: "example-com",
+							State:        goacm.ValidationRecordStateMismatch,
+							ActualValue:  "_old.value.missing.example.com",
+							Repaired:     true,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "error: not validated by DNS",
+			arns:    []string{ap[3].Certificate.Arn},
+			wantErr: true,
+			expect: []goacm.RenewalReadiness{
+				{
+					CertificateArn: ap[3].Certificate.Arn,
+					DomainName:     "email.example.com",
+					Error:          "certificate is not validated by DNS: " + ap[3].Certificate.Arn,
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			rAPI := goacm.NewMockRoute53API(rp)
+			if c.actualValue != "" {
+				rAPI.ListResourceRecordSetsAPI = func(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+					return &route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []route53Types.ResourceRecordSet{
+							{
+								Name:            params.StartRecordName,
+								Type:            route53Types.RRTypeCname,
+								ResourceRecords: []route53Types.ResourceRecord{{Value: aws.String(c.actualValue)}},
+							},
+						},
+					}, nil
+				}
+			}
+			changed := 0
+			change := rAPI.ChangeResourceRecordSetsAPI
+			rAPI.ChangeResourceRecordSetsAPI = func(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+				changed++
+				return change(ctx, params, optFns...)
+			}
+
+			report, err := goacm.CheckRenewalReadiness(context.TODO(), goacm.NewMockACMAPI(ap), rAPI, func(o *goacm.RenewalReadinessOptions) {
+				o.CertificateArns = c.arns
+				o.Repair = c.repair
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.Equal(tt, c.expect, report)
+			if !c.repair {
+				assert.Zero(tt, changed)
+			}
+		})
+	}
+}
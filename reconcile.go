@@ -0,0 +1,500 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+const (
+	// DefaultOwnerTagKey is a key of the tag that marks certificates managed by Reconcile.
+	DefaultOwnerTagKey = "goacm:owner"
+
+	// DefaultOwner is a value of the ownership tag if ReconcileOptions.Owner is empty.
+	DefaultOwner = "goacm"
+)
+
+// CertificateSpec is a structure that represents a desired certificate.
+type CertificateSpec struct {
+	DomainName              string   `json:"domainName" yaml:"domainName"`
+	SubjectAlternativeNames []string `json:"subjectAlternativeNames,omitempty" yaml:"subjectAlternativeNames,omitempty"`
+	HostedDomainName        string   `json:"hostedDomainName" yaml:"hostedDomainName"`
+
+	// ValidationMethod is DNS or EMAIL. Default is DNS.
+	ValidationMethod string `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+
+	// KeyAlgorithm is a key algorithm of the certificate such as RSA_2048.
+	// If it is empty, certificates of any key algorithm satisfy the spec.
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty" yaml:"keyAlgorithm,omitempty"`
+
+	// TransparencyLogging is ENABLED or DISABLED. Certificates with another preference are updated.
+	// If it is empty, certificates of any preference satisfy the spec.
+	TransparencyLogging string `json:"transparencyLogging,omitempty" yaml:"transparencyLogging,omitempty"`
+
+	// Tags are tags the certificate must have. Other tags of the certificate are kept.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Regions are regions where the certificate is needed. If it is empty, all regions of the clients are used.
+	Regions []string `json:"regions,omitempty" yaml:"regions,omitempty"`
+}
+
+// ReconcileOptions is a structure that represents options for Reconcile.
+type ReconcileOptions struct {
+	// OwnerTagKey is a key of the tag that marks certificates managed by Reconcile. Default is DefaultOwnerTagKey.
+	OwnerTagKey string
+
+	// Owner is a value of the ownership tag. Default is DefaultOwner.
+	// Use different owners to manage certificates of the same account from several sets of specs.
+	Owner string
+
+	// Prune deletes certificates that have the ownership tag but satisfy none of the specs.
+	Prune bool
+
+	// Wait makes it wait until the issued certificates become ISSUED. Default is false.
+	Wait bool
+
+	// WaitOptions are options for WaitCertificateIssued.
+	WaitOptions []func(*WaitCertificateOptions)
+
+	// DryRun returns the changes without making them. Only read APIs are called.
+	// ARNs of certificates to issue are empty.
+	DryRun bool
+}
+
+// ReconcileAction is a type that represents a change made by Reconcile.
+type ReconcileAction string
+
+// Changes made by Reconcile.
+const (
+	ReconcileActionIssue  ReconcileAction = "ISSUE"
+	ReconcileActionRetag  ReconcileAction = "RETAG"
+	ReconcileActionUpdate ReconcileAction = "UPDATE"
+	ReconcileActionDelete ReconcileAction = "DELETE"
+)
+
+// ReconcileChange is a structure that represents a change made to a certificate by Reconcile.
+type ReconcileChange struct {
+	Action         ReconcileAction `json:"action" yaml:"action"`
+	Region         string          `json:"region" yaml:"region"`
+	DomainName     string          `json:"domainName" yaml:"domainName"`
+	CertificateArn string          `json:"certificateArn,omitempty" yaml:"certificateArn,omitempty"`
+
+	// Tags are tags added to or updated in the certificate by RETAG.
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// TransparencyLogging is the preference set by UPDATE.
+	TransparencyLogging string `json:"transparencyLogging,omitempty" yaml:"transparencyLogging,omitempty"`
+
+	// Error is a message of the error if the change failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ReconcileResult is a structure that represents a result of Reconcile.
+type ReconcileResult struct {
+	Changes []ReconcileChange `json:"changes" yaml:"changes"`
+}
+
+// reconcileCertificate is a structure that represents an actual certificate with its tags.
+type reconcileCertificate struct {
+	Certificate
+	Tags map[string]string
+	kept bool
+}
+
+// Reconcile makes the certificates in the regions match the specs.
+// It issues certificates missing in regions, adds or updates tags of drifted certificates,
+// updates the transparency logging preference of drifted certificates,
+// and deletes certificates that have the ownership tag but satisfy none of the specs if Prune is set.
+// Certificates that satisfy a spec but do not have the ownership tag are adopted by adding it.
+// It continues with the other changes if a change fails, and returns the result with an error of the failed changes.
+func Reconcile(ctx context.Context, apis RegionalACMAPI, rAPI Route53API, specs []CertificateSpec, optFns ...func(*ReconcileOptions)) (ReconcileResult, error) {
+	o := ReconcileOptions{
+		OwnerTagKey: DefaultOwnerTagKey,
+		Owner:       DefaultOwner,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	regions := make([]string, 0, len(apis))
+	for r := range apis {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+
+	if err := checkSpecs(apis, regions, specs); err != nil {
+		return ReconcileResult{}, err
+	}
+
+	actual, err := listReconcileCertificates(ctx, apis, regions)
+	if err != nil {
+		return ReconcileResult{}, err
+	}
+
+	result := ReconcileResult{Changes: []ReconcileChange{}}
+	// domains validated by the records of certificates that satisfy the specs
+	keptDomains := map[string]bool{}
+
+	for _, spec := range specs {
+		specRegions := spec.Regions
+		if len(specRegions) == 0 {
+			specRegions = regions
+		}
+		tags := spec.desiredTags(o)
+		for _, d := range spec.domains() {
+			keptDomains[validationDomain(d)] = true
+		}
+
+		missing := []string{}
+		for _, r := range specRegions {
+			c := findSpecCertificate(actual[r], spec, o)
+			if c == nil {
+				missing = append(missing, r)
+				continue
+			}
+			c.kept = true
+
+			if spec.TransparencyLogging != "" && c.transparencyLogging() != spec.TransparencyLogging {
+				ch := ReconcileChange{
+					Action:              ReconcileActionUpdate,
+					Region:              r,
+					DomainName:          spec.DomainName,
+					CertificateArn:      c.Arn,
+					TransparencyLogging: spec.TransparencyLogging,
+				}
+				if !o.DryRun {
+					if err := UpdateCertificateTransparencyLogging(ctx, apis[r], c.Arn, spec.TransparencyLogging); err != nil {
+						ch.Error = err.Error()
+					}
+				}
+				result.Changes = append(result.Changes, ch)
+			}
+
+			drift := tagDrift(c.Tags, tags)
+			if len(drift) == 0 {
+				continue
+			}
+
+			ch := ReconcileChange{
+				Action:         ReconcileActionRetag,
+				Region:         r,
+				DomainName:     spec.DomainName,
+				CertificateArn: c.Arn,
+				Tags:           drift,
+			}
+			if o.DryRun {
+				result.Changes = append(result.Changes, ch)
+				continue
+			}
+			if err := AddCertificateTags(ctx, apis[r], c.Arn, drift); err != nil {
+				ch.Error = err.Error()
+			}
+			result.Changes = append(result.Changes, ch)
+		}
+
+		if len(missing) > 0 {
+			result.Changes = append(result.Changes, issueSpec(ctx, apis, rAPI, missing, spec, tags, o)...)
+		}
+	}
+
+	if o.Prune {
+		for _, r := range regions {
+			for _, c := range actual[r] {
+				if c.kept || c.Tags[o.OwnerTagKey] != o.Owner {
+					continue
+				}
+				result.Changes = append(result.Changes, pruneCertificate(ctx, apis, rAPI, r, c, keptDomains, o.DryRun))
+			}
+		}
+	}
+
+	msgs := []string{}
+	for _, ch := range result.Changes {
+		if ch.Error != "" {
+			msgs = append(msgs, fmt.Sprintf("%s %s in %s: %s", ch.Action, ch.DomainName, ch.Region, ch.Error))
+		}
+	}
+	if len(msgs) > 0 {
+		return result, errors.New(strings.Join(msgs, "; "))
+	}
+
+	return result, nil
+}
+
+// Reconcile makes the certificates in all regions match the specs.
+func (m *MultiRegionGoACM) Reconcile(ctx context.Context, specs []CertificateSpec, optFns ...func(*ReconcileOptions)) (ReconcileResult, error) {
+	return Reconcile(ctx, m.ACMAPIs(), m.Route53API(), specs, optFns...)
+}
+
+// Return an error if any spec is invalid, so that no change is made.
+func checkSpecs(apis RegionalACMAPI, regions []string, specs []CertificateSpec) error {
+	seen := map[string]bool{}
+	for i, spec := range specs {
+		if spec.DomainName == "" || spec.HostedDomainName == "" {
+			return fmt.Errorf("spec %d: domainName and hostedDomainName are required", i)
+		}
+
+		switch spec.method() {
+		case string(acmTypes.ValidationMethodDns), string(acmTypes.ValidationMethodEmail):
+		default:
+			return fmt.Errorf("spec %d: invalid validation method: %s", i, spec.ValidationMethod)
+		}
+
+		if err := ValidateKeyAlgorithm(spec.KeyAlgorithm); err != nil {
+			return fmt.Errorf("spec %d: %w", i, err)
+		}
+		if err := ValidateTransparencyLogging(spec.TransparencyLogging); err != nil {
+			return fmt.Errorf("spec %d: %w", i, err)
+		}
+
+		specRegions := spec.Regions
+		if len(specRegions) == 0 {
+			specRegions = regions
+		}
+		for _, r := range specRegions {
+			if _, ok := apis[r]; !ok {
+				return fmt.Errorf("spec %d: ACM client of the region does not exists: %s", i, r)
+			}
+			if seen[r+" "+spec.DomainName] {
+				return fmt.Errorf("spec %d: duplicated spec of %s in %s", i, spec.DomainName, r)
+			}
+			seen[r+" "+spec.DomainName] = true
+		}
+	}
+	return nil
+}
+
+// Return the certificates with their tags of each region.
+// It fails if any certificate cannot be described, because a certificate that looks missing would be issued again.
+func listReconcileCertificates(ctx context.Context, apis RegionalACMAPI, regions []string) (map[string][]*reconcileCertificate, error) {
+	actual := map[string][]*reconcileCertificate{}
+	for _, r := range regions {
+		summaries, err := ListCertificateSummaries(ctx, apis[r])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r, err)
+		}
+
+		for _, s := range summaries {
+			c, err := GetCertificate(ctx, apis[r], aws.ToString(s.CertificateArn))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r, err)
+			}
+			tags, err := ListCertificateTags(ctx, apis[r], c.Arn)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", r, err)
+			}
+			c.Region = r
+			actual[r] = append(actual[r], &reconcileCertificate{Certificate: c, Tags: tags})
+		}
+	}
+	return actual, nil
+}
+
+// Return the certificate that satisfies the spec.
+// Certificates with the ownership tag are preferred, and then issued ones.
+func findSpecCertificate(cList []*reconcileCertificate, spec CertificateSpec, o ReconcileOptions) *reconcileCertificate {
+	var found *reconcileCertificate
+	score := -1
+	for _, c := range cList {
+		if c.kept || !spec.satisfiedBy(c.Certificate) {
+			continue
+		}
+
+		s := 0
+		if c.Tags[o.OwnerTagKey] == o.Owner {
+			s += 2
+		}
+		if c.Status == string(acmTypes.CertificateStatusIssued) {
+			s++
+		}
+		if s > score {
+			found, score = c, s
+		}
+	}
+	return found
+}
+
+// Issue the certificate of the spec in the regions, and return the changes.
+// The certificates of the regions share the record that validates the domains.
+func issueSpec(ctx context.Context, apis RegionalACMAPI, rAPI Route53API, regions []string, spec CertificateSpec, tags map[string]string, o ReconcileOptions) []ReconcileChange {
+	changes := make([]ReconcileChange, 0, len(regions))
+	if o.DryRun {
+		for _, r := range regions {
+			changes = append(changes, ReconcileChange{
+				Action:     ReconcileActionIssue,
+				Region:     r,
+				DomainName: spec.DomainName,
+			})
+		}
+		return changes
+	}
+
+	res, err := ReplicateCertificate(ctx, apis, rAPI, regions, spec.method(), spec.DomainName, spec.HostedDomainName, func(ro *ReplicateCertificateOptions) {
+		ro.Wait = o.Wait
+		ro.WaitOptions = o.WaitOptions
+		ro.IssueOptions = []func(*IssueCertificateOptions){func(io *IssueCertificateOptions) {
+			io.SubjectAlternativeNames = spec.subjectAlternativeNames()
+			io.Tags = tags
+			io.KeyAlgorithm = spec.KeyAlgorithm
+			io.TransparencyLogging = spec.TransparencyLogging
+		}}
+	})
+
+	for i, r := range regions {
+		ch := ReconcileChange{
+			Action:     ReconcileActionIssue,
+			Region:     r,
+			DomainName: spec.DomainName,
+		}
+		if err != nil {
+			ch.Error = err.Error()
+		} else {
+			ch.CertificateArn = res.Regions[i].CertificateArn
+		}
+		changes = append(changes, ch)
+	}
+	return changes
+}
+
+// Delete the certificate, and return the change.
+// The records that validate the domains are kept if the specs use them, or if any certificate
+// in the regions of apis uses them, including unmanaged ones and ones of other owners.
+func pruneCertificate(ctx context.Context, apis RegionalACMAPI, rAPI Route53API, region string, c *reconcileCertificate, keptDomains map[string]bool, dryRun bool) ReconcileChange {
+	ch := ReconcileChange{
+		Action:         ReconcileActionDelete,
+		Region:         region,
+		DomainName:     c.DomainName,
+		CertificateArn: c.Arn,
+	}
+	if dryRun {
+		return ch
+	}
+
+	shared := false
+	for _, d := range append([]string{c.DomainName}, c.SubjectAlternativeNames...) {
+		if keptDomains[validationDomain(d)] {
+			shared = true
+		}
+	}
+
+	var err error
+	if shared {
+		err = deleteSharedACMCertificate(ctx, apis[region], c.Arn)
+	} else {
+		err = DeleteCertificate(ctx, apis[region], rAPI, c.Arn, func(o *DeleteCertificateOptions) {
+			o.Regions = apis
+		})
+	}
+	if err != nil {
+		ch.Error = err.Error()
+	}
+	return ch
+}
+
+// Delete only the certificate in ACM unless it is in use, keeping the records that other certificates use.
+func deleteSharedACMCertificate(ctx context.Context, api ACMAPI, arn string) error {
+	c, err := GetCertificate(ctx, api, arn)
+	if err != nil {
+		return err
+	}
+	if err := inUseError(arn, c.InUseBy); err != nil {
+		return err
+	}
+	return deleteACMCertificate(ctx, api, arn)
+}
+
+// Return the tags to add or update so that actual has all of desired.
+func tagDrift(actual, desired map[string]string) map[string]string {
+	drift := map[string]string{}
+	for k, v := range desired {
+		if av, ok := actual[k]; !ok || av != v {
+			drift[k] = v
+		}
+	}
+	return drift
+}
+
+// ACM validates a wildcard domain with the record of the base domain.
+func validationDomain(domainName string) string {
+	return strings.TrimPrefix(domainName, "*.")
+}
+
+func (s CertificateSpec) method() string {
+	if s.ValidationMethod == "" {
+		return string(acmTypes.ValidationMethodDns)
+	}
+	return s.ValidationMethod
+}
+
+// Return the sorted subject alternative names without the domain name and duplicates.
+func (s CertificateSpec) subjectAlternativeNames() []string {
+	return normalizeNames(s.DomainName, s.SubjectAlternativeNames)
+}
+
+func (s CertificateSpec) domains() []string {
+	return append([]string{s.DomainName}, s.subjectAlternativeNames()...)
+}
+
+// Return the tags of the spec with the ownership tag.
+func (s CertificateSpec) desiredTags(o ReconcileOptions) map[string]string {
+	tags := map[string]string{o.OwnerTagKey: o.Owner}
+	for k, v := range s.Tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// Return the transparency logging preference of the certificate. ACM enables it by default.
+func (c *reconcileCertificate) transparencyLogging() string {
+	if c.TransparencyLogging == "" {
+		return string(acmTypes.CertificateTransparencyLoggingPreferenceEnabled)
+	}
+	return c.TransparencyLogging
+}
+
+// Return whether the certificate satisfies the spec regardless of tags and options.
+func (s CertificateSpec) satisfiedBy(c Certificate) bool {
+	if c.Type != string(acmTypes.CertificateTypeAmazonIssued) || c.DomainName != s.DomainName {
+		return false
+	}
+
+	switch acmTypes.CertificateStatus(c.Status) {
+	case acmTypes.CertificateStatusIssued, acmTypes.CertificateStatusPendingValidation:
+	default:
+		return false
+	}
+
+	if s.KeyAlgorithm != "" && c.KeyAlgorithm != s.KeyAlgorithm {
+		return false
+	}
+
+	want := s.subjectAlternativeNames()
+	got := normalizeNames(c.DomainName, c.SubjectAlternativeNames)
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeNames(domainName string, names []string) []string {
+	seen := map[string]bool{domainName: true}
+	normalized := []string{}
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
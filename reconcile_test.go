@@ -0,0 +1,378 @@
+package goacm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Reconcile(t *testing.T) {
+	defer goacm.SetValidationRecordInterval(time.Millisecond)()
+
+	rs := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.www.example.com",
+		Value:            "_validation.value.www.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	oldRs := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.old.example.com",
+		Value:            "_validation.value.old.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	certificate := func(region, name, status string, recordSet goacm.RecordSet, tags map[string]string) goacm.MockACMParams {
+		return goacm.MockACMParams{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:" + region + ":000000000000:certificate/" + name,
+				DomainName:          name + ".example.com",
+				Status:              status,
+				Type:                string(types.CertificateTypeAmazonIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: recordSet,
+			},
+			Tags: tags,
+		}
+	}
+	owned := map[string]string{goacm.DefaultOwnerTagKey: goacm.DefaultOwner, "env": "prod"}
+
+	// existing are listed, and requestable are returned by RequestCertificate
+	regionalAPI := func(existing, requestable []goacm.MockACMParams) goacm.MockACMAPI {
+		api := goacm.NewMockACMAPI(append(existing, requestable...))
+		api.ListCertificatesAPI = goacm.NewMockACMListCertificatesAPI(existing)
+		return api
+	}
+
+	// an unmanaged certificate that validates old.example.com with the same record
+	manual := certificate("us-east-1", "manual", string(types.CertificateStatusIssued), oldRs, nil)
+	manual.Certificate.SubjectAlternativeNames = []string{"old.example.com"}
+
+	wwwSpec := goacm.CertificateSpec{
+		DomainName:       "www.example.com",
+		HostedDomainName: "example.com",
+		Tags:             map[string]string{"env": "prod"},
+	}
+
+	cases := []struct {
+		name                 string
+		apis                 goacm.RegionalACMAPI
+		route53Params        []goacm.MockRoute53Params
+		specs                []goacm.CertificateSpec
+		prune                bool
+		dryRun               bool
+		describeErr          string
+		wantErr              bool
+		expect               []goacm.ReconcileChange
+		expectDeletedRecords []string
+	}{
+		{
+			name: "normal: no changes",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+				}, nil),
+			},
+			specs:  []goacm.CertificateSpec{wwwSpec},
+			expect: []goacm.ReconcileChange{},
+		},
+		{
+			name: "normal: issue in the missing region with the existing record",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+				}, nil),
+				"ap-northeast-1": regionalAPI(nil, []goacm.MockACMParams{
+					certificate("ap-northeast-1", "www", string(types.CertificateStatusPendingValidation), rs, nil),
+				}),
+			},
+			route53Params: []goacm.MockRoute53Params{{RecordSet: rs}},
+			specs:         []goacm.CertificateSpec{wwwSpec},
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionIssue,
+					Region:         "ap-northeast-1",
+					DomainName:     "www.example.com",
+					CertificateArn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/www",
+				},
+			},
+		},
+		{
+			name: "normal: retag drifted and adopt unowned",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, map[string]string{"env": "dev", "team": "web"}),
+				}, nil),
+			},
+			specs: []goacm.CertificateSpec{wwwSpec},
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionRetag,
+					Region:         "us-east-1",
+					DomainName:     "www.example.com",
+					CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/www",
+					Tags:           owned,
+				},
+			},
+		},
+		{
+			name: "normal: update transparency logging",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+				}, nil),
+			},
+			specs: []goacm.CertificateSpec{
+				{
+					DomainName:          "www.example.com",
+					HostedDomainName:    "example.com",
+					TransparencyLogging: string(types.CertificateTransparencyLoggingPreferenceDisabled),
+					Tags:                map[string]string{"env": "prod"},
+				},
+			},
+			expect: []goacm.ReconcileChange{
+				{
+					Action:              goacm.ReconcileActionUpdate,
+					Region:              "us-east-1",
+					DomainName:          "www.example.com",
+					CertificateArn:      "arn:aws:acm:us-east-1:000000000000:certificate/www",
+					TransparencyLogging: string(types.CertificateTransparencyLoggingPreferenceDisabled),
+				},
+			},
+		},
+		{
+			name: "normal: prune owned certificates",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+					certificate("us-east-1", "old", string(types.CertificateStatusIssued), oldRs, owned),
+					manual,
+				}, nil),
+			},
+			route53Params: []goacm.MockRoute53Params{
+				{RecordSet: rs},
+				{RecordSet: oldRs, ChangeAction: route53Types.ChangeActionDelete},
+			},
+			specs: []goacm.CertificateSpec{wwwSpec},
+			prune: true,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionDelete,
+					Region:         "us-east-1",
+					DomainName:     "old.example.com",
+					CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/old",
+				},
+			},
+			// the unmanaged certificate uses the record
+			expectDeletedRecords: []string{},
+		},
+		{
+			name: "normal: prune owned certificates with the record nobody uses",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+					certificate("us-east-1", "old", string(types.CertificateStatusIssued), oldRs, owned),
+				}, nil),
+				"ap-northeast-1": regionalAPI([]goacm.MockACMParams{
+					certificate("ap-northeast-1", "www", string(types.CertificateStatusIssued), rs, owned),
+				}, nil),
+			},
+			route53Params: []goacm.MockRoute53Params{
+				{RecordSet: rs},
+				{RecordSet: oldRs, ChangeAction: route53Types.ChangeActionDelete},
+			},
+			specs: []goacm.CertificateSpec{wwwSpec},
+			prune: true,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionDelete,
+					Region:         "us-east-1",
+					DomainName:     "old.example.com",
+					CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/old",
+				},
+			},
+			expectDeletedRecords: []string{oldRs.Name},
+		},
+		{
+			name: "normal: keep the record that a certificate of another owner in another region uses",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+					certificate("us-east-1", "old", string(types.CertificateStatusIssued), oldRs, owned),
+				}, nil),
+				"ap-northeast-1": regionalAPI([]goacm.MockACMParams{
+					certificate("ap-northeast-1", "www", string(types.CertificateStatusIssued), rs, owned),
+					certificate("ap-northeast-1", "old", string(types.CertificateStatusIssued), oldRs, map[string]string{goacm.DefaultOwnerTagKey: "other"}),
+				}, nil),
+			},
+			route53Params: []goacm.MockRoute53Params{
+				{RecordSet: rs},
+				{RecordSet: oldRs, ChangeAction: route53Types.ChangeActionDelete},
+			},
+			specs: []goacm.CertificateSpec{wwwSpec},
+			prune: true,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionDelete,
+					Region:         "us-east-1",
+					DomainName:     "old.example.com",
+					CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/old",
+				},
+			},
+			expectDeletedRecords: []string{},
+		},
+		{
+			name: "normal: keep owned certificates without prune",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+					certificate("us-east-1", "old", string(types.CertificateStatusIssued), oldRs, owned),
+				}, nil),
+			},
+			specs:  []goacm.CertificateSpec{wwwSpec},
+			expect: []goacm.ReconcileChange{},
+		},
+		{
+			name: "normal: dry run",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, map[string]string{"env": "prod"}),
+					certificate("us-east-1", "old", string(types.CertificateStatusIssued), oldRs, owned),
+				}, nil),
+				"ap-northeast-1": regionalAPI(nil, nil),
+			},
+			specs:  []goacm.CertificateSpec{wwwSpec},
+			prune:  true,
+			dryRun: true,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:         goacm.ReconcileActionRetag,
+					Region:         "us-east-1",
+					DomainName:     "www.example.com",
+					CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/www",
+					Tags:           map[string]string{goacm.DefaultOwnerTagKey: goacm.DefaultOwner},
+				},
+				{
+					Action:     goacm.ReconcileActionIssue,
+					Region:     "ap-northeast-1",
+					DomainName: "www.example.com",
+				},
+				{
+					Action:         goacm.ReconcileActionDelete,
+					Region:         "us-east-1",
+					DomainName:     "old.example.com",
+					CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/old",
+				},
+			},
+		},
+		{
+			name: "error: failed to issue",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI(nil, nil),
+			},
+			specs:   []goacm.CertificateSpec{wwwSpec},
+			wantErr: true,
+			expect: []goacm.ReconcileChange{
+				{
+					Action:     goacm.ReconcileActionIssue,
+					Region:     "us-east-1",
+					DomainName: "www.example.com",
+					Error:      "us-east-1: domain name not available domain: www.example.com; rollbacked to replicate certificate",
+				},
+			},
+		},
+		{
+			name: "error: failed to describe a certificate",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": regionalAPI([]goacm.MockACMParams{
+					certificate("us-east-1", "www", string(types.CertificateStatusIssued), rs, owned),
+				}, []goacm.MockACMParams{
+					certificate("us-east-1", "www2", string(types.CertificateStatusPendingValidation), rs, nil),
+				}),
+			},
+			route53Params: []goacm.MockRoute53Params{{RecordSet: rs}},
+			specs:         []goacm.CertificateSpec{wwwSpec},
+			describeErr:   "arn:aws:acm:us-east-1:000000000000:certificate/www",
+			wantErr:       true,
+		},
+		{
+			name: "error: hosted domain is empty",
+			apis: goacm.RegionalACMAPI{"us-east-1": regionalAPI(nil, nil)},
+			specs: []goacm.CertificateSpec{
+				{DomainName: "www.example.com"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error: unsupported key algorithm",
+			apis: goacm.RegionalACMAPI{"us-east-1": regionalAPI(nil, nil)},
+			specs: []goacm.CertificateSpec{
+				{DomainName: "www.example.com", HostedDomainName: "example.com", KeyAlgorithm: string(types.KeyAlgorithmEcSecp521r1)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "error: unknown region",
+			apis: goacm.RegionalACMAPI{"us-east-1": regionalAPI(nil, nil)},
+			specs: []goacm.CertificateSpec{
+				{DomainName: "www.example.com", HostedDomainName: "example.com", Regions: []string{"eu-west-1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error: duplicated spec",
+			apis:    goacm.RegionalACMAPI{"us-east-1": regionalAPI(nil, nil)},
+			specs:   []goacm.CertificateSpec{wwwSpec, wwwSpec},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			for r, api := range c.apis {
+				mock := api.(goacm.MockACMAPI)
+				describe := mock.DescribeCertificateAPI
+				mock.DescribeCertificateAPI = func(ctx context.Context, params *acm.DescribeCertificateInput, optFns ...func(*acm.Options)) (*acm.DescribeCertificateOutput, error) {
+					if aws.ToString(params.CertificateArn) == c.describeErr {
+						return nil, errors.New("throttled")
+					}
+					return describe(ctx, params, optFns...)
+				}
+				c.apis[r] = mock
+			}
+
+			rAPI := goacm.NewMockRoute53API(c.route53Params)
+			deletedRecords := []string{}
+			change := rAPI.ChangeResourceRecordSetsAPI
+			rAPI.ChangeResourceRecordSetsAPI = func(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+				if params.ChangeBatch.Changes[0].Action == route53Types.ChangeActionDelete {
+					deletedRecords = append(deletedRecords, aws.ToString(params.ChangeBatch.Changes[0].ResourceRecordSet.Name))
+				}
+				return change(ctx, params, optFns...)
+			}
+
+			res, err := goacm.Reconcile(context.TODO(), c.apis, rAPI, c.specs, func(o *goacm.ReconcileOptions) {
+				o.Prune = c.prune
+				o.DryRun = c.dryRun
+			})
+			if c.wantErr {
+				assert.Error(tt, err)
+			} else {
+				assert.NoError(tt, err)
+			}
+
+			assert.Equal(tt, c.expect, res.Changes)
+			if c.expectDeletedRecords != nil {
+				assert.Equal(tt, c.expectDeletedRecords, deletedRecords)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/michimani/goacm"
+)
+
+// Plan writes the plan in the format. Tables are written in the diff-like format of PlanDiff.
+func Plan(w io.Writer, format Format, plan goacm.Plan) error {
+	switch format {
+	case FormatTable:
+		return PlanDiff(w, plan)
+	case FormatCSV:
+		return Items(w, format, plan.Changes, []string{"service", "action", "region", "domainName", "hostedZoneId", "recordSet.name", "recordSet.value", "certificateArn"})
+	}
+	return Item(w, format, plan, nil)
+}
+
+// PlanDiff writes the plan in a diff-like format.
+// Lines of resources to create start with "+", and lines of resources to delete start with "-".
+func PlanDiff(w io.Writer, plan goacm.Plan) error {
+	add, del := 0, 0
+	for _, ch := range plan.Changes {
+		mark := "+"
+		if ch.Action == goacm.PlanActionDelete {
+			mark = "-"
+			del++
+		} else {
+			add++
+		}
+
+		var line string
+		if ch.Service == goacm.ServiceRoute53 {
+			line = fmt.Sprintf("%s %s %s %s -> %s (zone %s)", mark, ch.Service, ch.RecordSet.Type, ch.RecordSet.Name, ch.RecordSet.Value, ch.HostedZoneID)
+		} else {
+			line = fmt.Sprintf("%s %s certificate %s", mark, ch.Service, ch.DomainName)
+			if len(ch.SubjectAlternativeNames) > 0 {
+				line += fmt.Sprintf(" [%s]", strings.Join(ch.SubjectAlternativeNames, ", "))
+			}
+			if ch.Region != "" {
+				line += fmt.Sprintf(" (%s)", ch.Region)
+			}
+			if ch.CertificateArn != "" {
+				line += " " + ch.CertificateArn
+			}
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\nPlan: %d to add, %d to delete.\n", add, del)
+	return err
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/michimani/goacm"
+)
+
+// ReconcileResult writes the result of Reconcile in the format.
+// Tables are written in the diff-like format of ReconcileDiff.
+func ReconcileResult(w io.Writer, format Format, r goacm.ReconcileResult) error {
+	switch format {
+	case FormatTable:
+		return ReconcileDiff(w, r)
+	case FormatCSV:
+		return Items(w, format, r.Changes, []string{"action", "region", "domainName", "certificateArn", "transparencyLogging", "error"})
+	}
+	return Item(w, format, r, nil)
+}
+
+// ReconcileDiff writes the result of Reconcile in a diff-like format.
+// Lines of issued certificates start with "+", retagged or updated with "~", and deleted with "-".
+// Failed changes are followed by a line of the error.
+func ReconcileDiff(w io.Writer, r goacm.ReconcileResult) error {
+	counts := map[goacm.ReconcileAction]int{}
+	failed := 0
+	for _, ch := range r.Changes {
+		counts[ch.Action]++
+
+		mark := "+"
+		switch ch.Action {
+		case goacm.ReconcileActionRetag, goacm.ReconcileActionUpdate:
+			mark = "~"
+		case goacm.ReconcileActionDelete:
+			mark = "-"
+		}
+
+		line := fmt.Sprintf("%s %s %s (%s)", mark, ch.Action, ch.DomainName, ch.Region)
+		if ch.CertificateArn != "" {
+			line += " " + ch.CertificateArn
+		}
+		if len(ch.Tags) > 0 {
+			line += " " + formatTags(ch.Tags)
+		}
+		if ch.TransparencyLogging != "" {
+			line += " transparency logging " + ch.TransparencyLogging
+		}
+		if ch.Error != "" {
+			failed++
+			line += "\n    error: " + ch.Error
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Changes) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "Reconcile: %d to issue, %d to retag, %d to update, %d to delete, %d failed.\n",
+		counts[goacm.ReconcileActionIssue], counts[goacm.ReconcileActionRetag], counts[goacm.ReconcileActionUpdate], counts[goacm.ReconcileActionDelete], failed)
+	return err
+}
+
+// Returns tags such as "{env=prod, team=web}" sorted by the keys.
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,314 @@
+// Package render writes goacm results in structured formats such as JSON, YAML, CSV and aligned tables.
+//
+// Field names follow the json and yaml struct tags of the goacm types.
+// Columns of CSV and tables are the field names, and nested fields are joined with ".",
+// such as "validationRecordSet.name".
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michimani/goacm"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a type that represents an output format.
+type Format string
+
+// Output formats.
+const (
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+	FormatTable Format = "table"
+)
+
+// Formats is a list of all output formats.
+var Formats = []Format{FormatJSON, FormatYAML, FormatCSV, FormatTable}
+
+// DefaultCertificateColumns is a list of columns used for certificates if Options.Columns is empty.
+var DefaultCertificateColumns = []string{"region", "domainName", "status", "type", "arn"}
+
+// DefaultIssueCertificateResultColumns is a list of columns used for IssueCertificateResult if Options.Columns is empty.
+var DefaultIssueCertificateResultColumns = []string{"domainName", "validationMethod", "hostedZoneId", "validationRecordName", "validationRecordValue", "certificateArn"}
+
+// Options is a structure that represents options for rendering.
+type Options struct {
+	// Columns is a list of columns of CSV and tables. JSON and YAML always contain all fields.
+	Columns []string
+
+	// SortBy is a column to sort items by. Items keep the original order if it is empty.
+	SortBy string
+
+	// Descending sorts items in descending order.
+	Descending bool
+
+	// MaxWidth is a maximum width of a table line. Long cells are truncated to fit in it.
+	// No limit if it is 0.
+	MaxWidth int
+}
+
+// ColumnError is an error that represents an unknown column in Options.
+type ColumnError struct {
+	Column string
+}
+
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("unknown column: %s", e.Column)
+}
+
+// ParseFormat returns the format of the name.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format: %s", s)
+}
+
+// Certificates writes the certificates in the format.
+func Certificates(w io.Writer, format Format, cList []goacm.Certificate, optFns ...func(*Options)) error {
+	if cList == nil {
+		cList = []goacm.Certificate{}
+	}
+	return Items(w, format, cList, DefaultCertificateColumns, optFns...)
+}
+
+// Certificate writes the certificate in the format.
+func Certificate(w io.Writer, format Format, c goacm.Certificate, optFns ...func(*Options)) error {
+	return Item(w, format, c, DefaultCertificateColumns, optFns...)
+}
+
+// IssueCertificateResult writes the result of IssueCertificate in the format.
+func IssueCertificateResult(w io.Writer, format Format, r goacm.IssueCertificateResult, optFns ...func(*Options)) error {
+	return Item(w, format, r, DefaultIssueCertificateResultColumns, optFns...)
+}
+
+// Item writes a struct in the format. JSON and YAML are an object instead of an array,
+// and CSV and tables have a single row.
+func Item(w io.Writer, format Format, item interface{}, defaultColumns []string, optFns ...func(*Options)) error {
+	switch format {
+	case FormatJSON, FormatYAML:
+		return encode(w, format, item)
+	}
+
+	items := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(item)), 0, 1)
+	items = reflect.Append(items, reflect.ValueOf(item))
+	return Items(w, format, items.Interface(), defaultColumns, optFns...)
+}
+
+// Items writes a slice of structs in the format.
+// defaultColumns is used for CSV and tables if Options.Columns is empty.
+func Items(w io.Writer, format Format, items interface{}, defaultColumns []string, optFns ...func(*Options)) error {
+	o := Options{}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+	if len(o.Columns) == 0 {
+		o.Columns = defaultColumns
+	}
+
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("items must be a slice: %T", items)
+	}
+
+	rows := make([]map[string]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		rows[i] = map[string]string{}
+		flatten(rows[i], "", v.Index(i))
+	}
+
+	order, err := sortOrder(rows, o)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatJSON, FormatYAML:
+		sorted := reflect.MakeSlice(v.Type(), 0, v.Len())
+		for _, i := range order {
+			sorted = reflect.Append(sorted, v.Index(i))
+		}
+		return encode(w, format, sorted.Interface())
+	case FormatCSV, FormatTable:
+		if err := checkColumns(v.Type().Elem(), o.Columns); err != nil {
+			return err
+		}
+
+		table := make([][]string, 0, len(order)+1)
+		table = append(table, o.Columns)
+		for _, i := range order {
+			cells := make([]string, len(o.Columns))
+			for j, c := range o.Columns {
+				cells[j] = rows[i][c]
+			}
+			table = append(table, cells)
+		}
+
+		if format == FormatCSV {
+			cw := csv.NewWriter(w)
+			if err := cw.WriteAll(table); err != nil {
+				return err
+			}
+			return cw.Error()
+		}
+		return writeTable(w, table, o.MaxWidth)
+	}
+
+	return fmt.Errorf("unknown format: %s", format)
+}
+
+// Encode v in JSON or YAML.
+func encode(w io.Writer, format Format, v interface{}) error {
+	if format == FormatJSON {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return e.Encode(v)
+	}
+
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// Returns the order of rows sorted by the column of the options.
+func sortOrder(rows []map[string]string, o Options) ([]int, error) {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	if o.SortBy == "" || len(rows) == 0 {
+		return order, nil
+	}
+
+	if _, ok := rows[0][o.SortBy]; !ok {
+		return nil, &ColumnError{Column: o.SortBy}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := rows[order[i]][o.SortBy], rows[order[j]][o.SortBy]
+		if o.Descending {
+			return a > b
+		}
+		return a < b
+	})
+	return order, nil
+}
+
+// Returns an error if a column is not a field of the type.
+func checkColumns(t reflect.Type, columns []string) error {
+	known := map[string]string{}
+	flatten(known, "", reflect.Zero(t))
+	for _, c := range columns {
+		if _, ok := known[c]; !ok {
+			return &ColumnError{Column: c}
+		}
+	}
+	return nil
+}
+
+// Flatten fields of the struct into row with names of json tags.
+func flatten(row map[string]string, prefix string, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := fieldName(f)
+		if name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && !isTime(fv) {
+			flatten(row, key+".", fv)
+			continue
+		}
+		row[key] = cell(fv)
+	}
+}
+
+// Returns the name of the field in the json tag, or the field name.
+func fieldName(f reflect.StructField) string {
+	tag := strings.Split(f.Tag.Get("json"), ",")[0]
+	if tag != "" {
+		return tag
+	}
+	return f.Name
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isTime(v reflect.Value) bool {
+	return v.Type() == timeType
+}
+
+// Returns a string of the value for CSV and tables.
+func cell(v reflect.Value) string {
+	if isTime(v) {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		s := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s[i] = cell(v.Index(i))
+		}
+		return strings.Join(s, ",")
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(k.Interface()))
+		}
+		sort.Strings(keys)
+		s := make([]string, len(keys))
+		for i, k := range keys {
+			s[i] = k + "=" + cell(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())))
+		}
+		return strings.Join(s, ",")
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return cell(v.Elem())
+	}
+	return fmt.Sprint(v.Interface())
+}
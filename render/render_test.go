@@ -0,0 +1,356 @@
+package render_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/michimani/goacm"
+	"github.com/michimani/goacm/render"
+	"github.com/stretchr/testify/assert"
+)
+
+var certificates = []goacm.Certificate{
+	{
+		Arn:        "arn:aws:acm:us-east-1:000000000000:certificate/cdn",
+		Region:     "us-east-1",
+		DomainName: "cdn.example.com",
+		Type:       "AMAZON_ISSUED",
+		Status:     "ISSUED",
+	},
+	{
+		Arn:              "arn:aws:acm:ap-northeast-1:000000000000:certificate/app",
+		Region:           "ap-northeast-1",
+		DomainName:       "app.example.com",
+		Type:             "AMAZON_ISSUED",
+		Status:           "PENDING_VALIDATION",
+		ValidationMethod: "DNS",
+		ValidationRecordSet: goacm.RecordSet{
+			HostedDomainName: "example.com",
+			Name:             "_x.app.example.com.",
+			Value:            "_y.acm-validations.aws.",
+			Type:             "CNAME",
+		},
+	},
+}
+
+func Test_Certificates(t *testing.T) {
+	cases := []struct {
+		name    string
+		format  render.Format
+		optFns  []func(*render.Options)
+		wantErr bool
+		expect  string
+	}{
+		{
+			name:   "normal: table",
+			format: render.FormatTable,
+			expect: "" +
+				"REGION          DOMAINNAME       STATUS              TYPE           ARN\n" +
+				"us-east-1       cdn.example.com  ISSUED              AMAZON_ISSUED  arn:aws:acm:us-east-1:000000000000:certificate/cdn\n" +
+				"ap-northeast-1  app.example.com  PENDING_VALIDATION  AMAZON_ISSUED  arn:aws:acm:ap-northeast-1:000000000000:certificate/app\n",
+		},
+		{
+			name:   "normal: table with columns, sorting and width",
+			format: render.FormatTable,
+			optFns: []func(*render.Options){func(o *render.Options) {
+				o.Columns = []string{"domainName", "validationRecordSet.name"}
+				o.SortBy = "domainName"
+				o.MaxWidth = 30
+			}},
+			expect: "" +
+				"DOMAINNAME      VALIDATIONREC…\n" +
+				"app.example.c…  _x.app.exampl…\n" +
+				"cdn.example.c…  \n",
+		},
+		{
+			name:   "normal: csv sorted in descending order",
+			format: render.FormatCSV,
+			optFns: []func(*render.Options){func(o *render.Options) {
+				o.Columns = []string{"domainName", "status"}
+				o.SortBy = "status"
+				o.Descending = true
+			}},
+			expect: "" +
+				"domainName,status\n" +
+				"app.example.com,PENDING_VALIDATION\n" +
+				"cdn.example.com,ISSUED\n",
+		},
+		{
+			name:   "normal: json",
+			format: render.FormatJSON,
+			optFns: []func(*render.Options){func(o *render.Options) {
+				o.SortBy = "region"
+			}},
+			expect: `[
+  {
+    "arn": "arn:aws:acm:ap-northeast-1:000000000000:certificate/app",
+    "region": "ap-northeast-1",
+    "domainName": "app.example.com",
+    "type": "AMAZON_ISSUED",
+    "status": "PENDING_VALIDATION",
+    "validationMethod": "DNS",
+    "validationRecordSet": {
+      "hostedDomainName": "example.com",
+      "name": "_x.app.example.com.",
+      "value": "_y.acm-validations.aws.",
+      "type": "CNAME"
+    }
+  },
+  {
+    "arn": "arn:aws:acm:us-east-1:000000000000:certificate/cdn",
+    "region": "us-east-1",
+    "domainName": "cdn.example.com",
+    "type": "AMAZON_ISSUED",
+    "status": "ISSUED",
+    "validationRecordSet": {
+      "hostedDomainName": "",
+      "name": "",
+      "value": "",
+      "type": ""
+    }
+  }
+]
+`,
+		},
+		{
+			name:   "normal: yaml",
+			format: render.FormatYAML,
+			optFns: []func(*render.Options){func(o *render.Options) {
+				o.SortBy = "region"
+				o.Descending = true
+			}},
+			expect: `- arn: arn:aws:acm:us-east-1:000000000000:certificate/cdn
+  region: us-east-1
+  domainName: cdn.example.com
+  type: AMAZON_ISSUED
+  status: ISSUED
+  validationRecordSet:
+    hostedDomainName: ""
+    name: ""
+    value: ""
+    type: ""
+- arn: arn:aws:acm:ap-northeast-1:000000000000:certificate/app
+  region: ap-northeast-1
+  domainName: app.example.com
+  type: AMAZON_ISSUED
+  status: PENDING_VALIDATION
+  validationMethod: DNS
+  validationRecordSet:
+    hostedDomainName: example.com
+    name: _x.app.example.com.
+    value: _y.acm-validations.aws.
+    type: CNAME
+`,
+		},
+		{
+			name:   "error: unknown column",
+			format: render.FormatCSV,
+			optFns: []func(*render.Options){func(o *render.Options) {
+				o.Columns = []string{"unknown"}
+			}},
+			wantErr: true,
+		},
+		{
+			name:   "error: unknown sort column",
+			format: render.FormatJSON,
+			optFns: []func(*render.Options){func(o *render.Options) {
+				o.SortBy = "unknown"
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "error: unknown format",
+			format:  render.Format("xml"),
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			b := &bytes.Buffer{}
+			err := render.Certificates(b, c.format, certificates, c.optFns...)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, b.String())
+		})
+	}
+}
+
+func Test_IssueCertificateResult(t *testing.T) {
+	r := goacm.IssueCertificateResult{
+		CertificateArn:        "arn:aws:acm:ap-northeast-1:000000000000:certificate/app",
+		DomainName:            "app.example.com",
+		HostedDomainName:      "example.com",
+		HosteZoneID:           "Z000000000000",
+		ValidationMethod:      "DNS",
+		ValidationRecordName:  "_x.app.example.com.",
+		ValidationRecordValue: "_y.acm-validations.aws.",
+	}
+
+	cases := []struct {
+		name   string
+		format render.Format
+		expect string
+	}{
+		{
+			name:   "normal: csv",
+			format: render.FormatCSV,
+			expect: "" +
+				"domainName,validationMethod,hostedZoneId,validationRecordName,validationRecordValue,certificateArn\n" +
+				"app.example.com,DNS,Z000000000000,_x.app.example.com.,_y.acm-validations.aws.,arn:aws:acm:ap-northeast-1:000000000000:certificate/app\n",
+		},
+		{
+			name:   "normal: json",
+			format: render.FormatJSON,
+			expect: `{
+  "certificateArn": "arn:aws:acm:ap-northeast-1:000000000000:certificate/app",
+  "domainName": "app.example.com",
+  "hostedDomainName": "example.com",
+  "hostedZoneId": "Z000000000000",
+  "validationMethod": "DNS",
+  "validationRecordName": "_x.app.example.com.",
+  "validationRecordValue": "_y.acm-validations.aws."
+}
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			b := &bytes.Buffer{}
+			err := render.IssueCertificateResult(b, c.format, r)
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, b.String())
+		})
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       string
+		wantErr bool
+		expect  render.Format
+	}{
+		{name: "normal", s: "yaml", expect: render.FormatYAML},
+		{name: "normal: upper case", s: "JSON", expect: render.FormatJSON},
+		{name: "error: unknown", s: "xml", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			f, err := render.ParseFormat(c.s)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, f)
+		})
+	}
+}
+
+func Test_PlanDiff(t *testing.T) {
+	plan := goacm.Plan{Changes: []goacm.PlannedChange{
+		{
+			Service:                 goacm.ServiceACM,
+			Action:                  goacm.PlanActionRequest,
+			Region:                  "ap-northeast-1",
+			DomainName:              "example.com",
+			SubjectAlternativeNames: []string{"*.example.com"},
+		},
+		{
+			Service:      goacm.ServiceRoute53,
+			Action:       goacm.PlanActionCreate,
+			DomainName:   "example.com",
+			HostedZoneID: "Z000000000000",
+			RecordSet: goacm.RecordSet{
+				Name:  goacm.UnknownRecordValue,
+				Value: goacm.UnknownRecordValue,
+				Type:  "CNAME",
+			},
+		},
+		{
+			Service:      goacm.ServiceRoute53,
+			Action:       goacm.PlanActionDelete,
+			DomainName:   "old.example.com",
+			HostedZoneID: "Z000000000000",
+			RecordSet: goacm.RecordSet{
+				Name:  "_x.old.example.com.",
+				Value: "_y.acm-validations.aws.",
+				Type:  "CNAME",
+			},
+		},
+		{
+			Service:        goacm.ServiceACM,
+			Action:         goacm.PlanActionDelete,
+			Region:         "ap-northeast-1",
+			CertificateArn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/old",
+			DomainName:     "old.example.com",
+		},
+	}}
+
+	expect := "" +
+		"+ ACM certificate example.com [*.example.com] (ap-northeast-1)\n" +
+		"+ Route 53 CNAME (known after request) -> (known after request) (zone Z000000000000)\n" +
+		"- Route 53 CNAME _x.old.example.com. -> _y.acm-validations.aws. (zone Z000000000000)\n" +
+		"- ACM certificate old.example.com (ap-northeast-1) arn:aws:acm:ap-northeast-1:000000000000:certificate/old\n" +
+		"\n" +
+		"Plan: 2 to add, 2 to delete.\n"
+
+	b := &bytes.Buffer{}
+	err := render.Plan(b, render.FormatTable, plan)
+	assert.NoError(t, err)
+	assert.Equal(t, expect, b.String())
+}
+
+func Test_ReconcileDiff(t *testing.T) {
+	r := goacm.ReconcileResult{Changes: []goacm.ReconcileChange{
+		{
+			Action:         goacm.ReconcileActionIssue,
+			Region:         "ap-northeast-1",
+			DomainName:     "www.example.com",
+			CertificateArn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/www",
+		},
+		{
+			Action:         goacm.ReconcileActionRetag,
+			Region:         "us-east-1",
+			DomainName:     "www.example.com",
+			CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/www",
+			Tags:           map[string]string{"team": "web", "env": "prod"},
+		},
+		{
+			Action:              goacm.ReconcileActionUpdate,
+			Region:              "us-east-1",
+			DomainName:          "internal.example.com",
+			CertificateArn:      "arn:aws:acm:us-east-1:000000000000:certificate/internal",
+			TransparencyLogging: "DISABLED",
+		},
+		{
+			Action:         goacm.ReconcileActionDelete,
+			Region:         "us-east-1",
+			DomainName:     "old.example.com",
+			CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/old",
+			Error:          "certificate is in use",
+		},
+	}}
+
+	expect := "" +
+		"+ ISSUE www.example.com (ap-northeast-1) arn:aws:acm:ap-northeast-1:000000000000:certificate/www\n" +
+		"~ RETAG www.example.com (us-east-1) arn:aws:acm:us-east-1:000000000000:certificate/www {env=prod, team=web}\n" +
+		"~ UPDATE internal.example.com (us-east-1) arn:aws:acm:us-east-1:000000000000:certificate/internal transparency logging DISABLED\n" +
+		"- DELETE old.example.com (us-east-1) arn:aws:acm:us-east-1:000000000000:certificate/old\n" +
+		"    error: certificate is in use\n" +
+		"\n" +
+		"Reconcile: 1 to issue, 1 to retag, 1 to update, 1 to delete, 1 failed.\n"
+
+	b := &bytes.Buffer{}
+	err := render.ReconcileResult(b, render.FormatTable, r)
+	assert.NoError(t, err)
+	assert.Equal(t, expect, b.String())
+}
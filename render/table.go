@@ -0,0 +1,94 @@
+package render
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// columnSeparator is a separator of table columns.
+	columnSeparator = "  "
+
+	// ellipsis is appended to truncated cells.
+	ellipsis = "…"
+
+	// minColumnWidth is a minimum width of a truncated column.
+	minColumnWidth = 4
+)
+
+// Write the table with aligned columns. The first row is the header.
+// If maxWidth is positive, the widest columns are truncated until the line fits in maxWidth.
+func writeTable(w io.Writer, table [][]string, maxWidth int) error {
+	if len(table) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(table[0]))
+	for _, row := range table {
+		for i, c := range row {
+			if n := utf8.RuneCountInString(c); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	if maxWidth > 0 {
+		fitWidths(widths, maxWidth)
+	}
+
+	var b strings.Builder
+	for r, row := range table {
+		b.Reset()
+		for i, c := range row {
+			c = truncate(c, widths[i])
+			if r == 0 {
+				c = strings.ToUpper(c)
+			}
+			b.WriteString(c)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c)))
+				b.WriteString(columnSeparator)
+			}
+		}
+		b.WriteString("\n")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Shrink the widest column one by one until the total width fits in maxWidth.
+func fitWidths(widths []int, maxWidth int) {
+	total := func() int {
+		t := len(columnSeparator) * (len(widths) - 1)
+		for _, w := range widths {
+			t += w
+		}
+		return t
+	}
+
+	for total() > maxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+// Truncate s to width runes with an ellipsis.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + ellipsis
+}
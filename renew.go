@@ -0,0 +1,122 @@
+package goacm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// RenewalSummary is a structure that represents the status of the managed renewal of a certificate.
+type RenewalSummary struct {
+	// Eligibility is ELIGIBLE or INELIGIBLE. Certificates that are not in use are ineligible for managed renewal.
+	Eligibility string `json:"eligibility,omitempty" yaml:"eligibility,omitempty"`
+
+	// Status is PENDING_AUTO_RENEWAL, PENDING_VALIDATION, SUCCESS or FAILED, or empty if renewal has not started.
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// Reason is the reason of the failure of the renewal.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	UpdatedAt         *time.Time         `json:"updatedAt,omitempty" yaml:"updatedAt,omitempty"`
+	DomainValidations []DomainValidation `json:"domainValidations,omitempty" yaml:"domainValidations,omitempty"`
+}
+
+// DomainValidation is a structure that represents the validation state of a domain of a certificate.
+type DomainValidation struct {
+	DomainName       string `json:"domainName" yaml:"domainName"`
+	ValidationDomain string `json:"validationDomain,omitempty" yaml:"validationDomain,omitempty"`
+	ValidationMethod string `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+
+	// ValidationStatus is PENDING_VALIDATION, SUCCESS or FAILED.
+	ValidationStatus string `json:"validationStatus,omitempty" yaml:"validationStatus,omitempty"`
+
+	// ValidationRecordSet is the record that validates the domain by DNS.
+	ValidationRecordSet *RecordSet `json:"validationRecordSet,omitempty" yaml:"validationRecordSet,omitempty"`
+}
+
+// RenewCertificate renews a private certificate issued by AWS Private CA before it expires.
+// Certificates issued by Amazon are renewed by ACM, and only private certificates that are eligible can be renewed.
+// The progress of the renewal is returned by RenewalStatus.
+func RenewCertificate(ctx context.Context, api ACMDescribeRenewCertificateAPI, arn string) error {
+	c, err := GetCertificate(ctx, api, arn)
+	if err != nil {
+		return err
+	}
+
+	if c.Type != string(acmTypes.CertificateTypePrivate) {
+		return fmt.Errorf("only private certificates can be renewed: %s is %s", arn, c.Type)
+	}
+	if c.RenewalEligibility == string(acmTypes.RenewalEligibilityIneligible) {
+		return fmt.Errorf("certificate is not eligible for renewal: %s", arn)
+	}
+
+	in := acm.RenewCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+	_, err = api.RenewCertificate(ctx, &in)
+	return err
+}
+
+// RenewalStatus returns the status of the managed renewal of the certificate,
+// with the validation state of each domain to diagnose renewals stuck in PENDING_AUTO_RENEWAL.
+func RenewalStatus(ctx context.Context, api ACMDescribeCertificateAPI, arn string) (RenewalSummary, error) {
+	in := acm.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+	out, err := api.DescribeCertificate(ctx, &in)
+	if err != nil {
+		return RenewalSummary{}, err
+	}
+
+	d := out.Certificate
+	summary := RenewalSummary{
+		Eligibility: string(d.RenewalEligibility),
+	}
+	if d.RenewalSummary == nil {
+		return summary, nil
+	}
+
+	summary.Status = string(d.RenewalSummary.RenewalStatus)
+	summary.Reason = string(d.RenewalSummary.RenewalStatusReason)
+	summary.UpdatedAt = d.RenewalSummary.UpdatedAt
+	summary.DomainValidations = toDomainValidations(d.RenewalSummary.DomainValidationOptions)
+
+	return summary, nil
+}
+
+// Returns the validation state of the domains.
+func toDomainValidations(dvs []acmTypes.DomainValidation) []DomainValidation {
+	var validations []DomainValidation
+	for _, dv := range dvs {
+		v := DomainValidation{
+			DomainName:       aws.ToString(dv.DomainName),
+			ValidationDomain: aws.ToString(dv.ValidationDomain),
+			ValidationMethod: string(dv.ValidationMethod),
+			ValidationStatus: string(dv.ValidationStatus),
+		}
+		if dv.ResourceRecord != nil {
+			v.ValidationRecordSet = &RecordSet{
+				HostedDomainName: aws.ToString(dv.ValidationDomain),
+				Name:             aws.ToString(dv.ResourceRecord.Name),
+				Value:            aws.ToString(dv.ResourceRecord.Value),
+				Type:             string(dv.ResourceRecord.Type),
+			}
+		}
+		validations = append(validations, v)
+	}
+	return validations
+}
+
+// RenewCertificate renews a private certificate with the ACM client.
+func (g *GoACM) RenewCertificate(ctx context.Context, arn string) error {
+	return RenewCertificate(ctx, g.ACMAPI(), arn)
+}
+
+// RenewalStatus returns the status of the managed renewal of the certificate with the ACM client.
+func (g *GoACM) RenewalStatus(ctx context.Context, arn string) (RenewalSummary, error) {
+	return RenewalStatus(ctx, g.ACMAPI(), arn)
+}
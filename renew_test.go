@@ -0,0 +1,215 @@
+package goacm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RenewCertificate(t *testing.T) {
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:                "arn:aws:acm:ap-northeast-1:000000000000:certificate/private",
+				Type:               string(types.CertificateTypePrivate),
+				RenewalEligibility: string(types.RenewalEligibilityEligible),
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:                "arn:aws:acm:ap-northeast-1:000000000000:certificate/ineligible",
+				Type:               string(types.CertificateTypePrivate),
+				RenewalEligibility: string(types.RenewalEligibilityIneligible),
+			},
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:                "arn:aws:acm:ap-northeast-1:000000000000:certificate/public",
+				Type:               string(types.CertificateTypeAmazonIssued),
+				RenewalEligibility: string(types.RenewalEligibilityEligible),
+			},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		arn     string
+		wantErr bool
+	}{
+		{
+			name: "normal",
+			arn:  ap[0].Certificate.Arn,
+		},
+		{
+			name:    "error: ineligible",
+			arn:     ap[1].Certificate.Arn,
+			wantErr: true,
+		},
+		{
+			name:    "error: not a private certificate",
+			arn:     ap[2].Certificate.Arn,
+			wantErr: true,
+		},
+		{
+			name:    "error: not found",
+			arn:     "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			aAPI := goacm.NewMockACMAPI(ap)
+			renewed := false
+			renew := aAPI.RenewCertificateAPI
+			aAPI.RenewCertificateAPI = func(ctx context.Context, params *acm.RenewCertificateInput, optFns ...func(*acm.Options)) (*acm.RenewCertificateOutput, error) {
+				renewed = true
+				return renew(ctx, params, optFns...)
+			}
+
+			err := goacm.RenewCertificate(context.TODO(), aAPI, c.arn)
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.False(tt, renewed)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.True(tt, renewed)
+		})
+	}
+}
+
+func Test_RenewalStatus(t *testing.T) {
+	updatedAt := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+	pending := goacm.RenewalSummary{
+		Eligibility: string(types.RenewalEligibilityEligible),
+		Status:      string(types.RenewalStatusPendingAutoRenewal),
+		UpdatedAt:   &updatedAt,
+		DomainValidations: []goacm.DomainValidation{
+			{
+				DomainName:       "test.example.com",
+				ValidationDomain: "example.com",
+				ValidationMethod: string(types.ValidationMethodDns),
+				ValidationStatus: string(types.DomainStatusPendingValidation),
+				ValidationRecordSet: &goacm.RecordSet{
+					HostedDomainName: "example.com",
+					Name:             "_validation.name.test.example.com",
+					Value:            "_validation.value.test.example.com",
+					Type:             string(route53Types.RRTypeCname),
+				},
+			},
+		},
+	}
+	failed := goacm.RenewalSummary{
+		Eligibility: string(types.RenewalEligibilityEligible),
+		Status:      string(types.RenewalStatusFailed),
+		Reason:      string(types.FailureReasonCaaError),
+		UpdatedAt:   &updatedAt,
+	}
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn:                "arn:aws:acm:ap-northeast-1:000000000000:certificate/pending",
+				RenewalEligibility: string(types.RenewalEligibilityEligible),
+			},
+			RenewalSummary: &pending,
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:                "arn:aws:acm:ap-northeast-1:000000000000:certificate/failed",
+				RenewalEligibility: string(types.RenewalEligibilityEligible),
+			},
+			RenewalSummary: &failed,
+		},
+		{
+			Certificate: goacm.Certificate{
+				Arn:                "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-renewed",
+				RenewalEligibility: string(types.RenewalEligibilityIneligible),
+			},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		arn     string
+		wantErr bool
+		expect  goacm.RenewalSummary
+	}{
+		{
+			name:   "normal: pending auto renewal",
+			arn:    ap[0].Certificate.Arn,
+			expect: pending,
+		},
+		{
+			name:   "normal: failed",
+			arn:    ap[1].Certificate.Arn,
+			expect: failed,
+		},
+		{
+			name: "normal: renewal not started",
+			arn:  ap[2].Certificate.Arn,
+			expect: goacm.RenewalSummary{
+				Eligibility: string(types.RenewalEligibilityIneligible),
+			},
+		},
+		{
+			name:    "error: not found",
+			arn:     "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			s, err := goacm.RenewalStatus(context.TODO(), goacm.NewMockACMAPI(ap), c.arn)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, s)
+		})
+	}
+}
+
+func Test_RenewalSummary_JSON(t *testing.T) {
+	updatedAt := time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name    string
+		summary goacm.RenewalSummary
+		expect  string
+	}{
+		{
+			name:    "normal: renewal not started",
+			summary: goacm.RenewalSummary{Eligibility: string(types.RenewalEligibilityIneligible)},
+			expect:  `{"eligibility":"INELIGIBLE"}`,
+		},
+		{
+			name: "normal: renewal updated",
+			summary: goacm.RenewalSummary{
+				Eligibility: string(types.RenewalEligibilityEligible),
+				Status:      string(types.RenewalStatusSuccess),
+				UpdatedAt:   &updatedAt,
+			},
+			expect: `{"eligibility":"ELIGIBLE","status":"SUCCESS","updatedAt":"2021-12-01T00:00:00Z"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			b, err := json.Marshal(c.summary)
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, string(b))
+		})
+	}
+}
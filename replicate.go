@@ -0,0 +1,303 @@
+package goacm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// ReplicateCertificateOptions is a structure that represents options for ReplicateCertificate.
+type ReplicateCertificateOptions struct {
+	// Wait makes it wait until the certificates of all regions are issued. Default is true.
+	Wait bool
+
+	// WaitOptions are options for WaitCertificateIssued.
+	WaitOptions []func(*WaitCertificateOptions)
+
+	// IssueOptions are options for requesting the certificate in each region.
+	IssueOptions []func(*IssueCertificateOptions)
+}
+
+// ReplicateCertificateResult is a structure that represents a result of ReplicateCertificate.
+type ReplicateCertificateResult struct {
+	DomainName            string                      `json:"domainName" yaml:"domainName"`
+	HostedDomainName      string                      `json:"hostedDomainName,omitempty" yaml:"hostedDomainName,omitempty"`
+	HostedZoneID          string                      `json:"hostedZoneId,omitempty" yaml:"hostedZoneId,omitempty"`
+	ValidationMethod      string                      `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+	ValidationRecordName  string                      `json:"validationRecordName,omitempty" yaml:"validationRecordName,omitempty"`
+	ValidationRecordValue string                      `json:"validationRecordValue,omitempty" yaml:"validationRecordValue,omitempty"`
+	Regions               []RegionalCertificateResult `json:"regions" yaml:"regions"`
+}
+
+// RegionalCertificateResult is a structure that represents a certificate of a region in ReplicateCertificateResult.
+type RegionalCertificateResult struct {
+	Region         string `json:"region" yaml:"region"`
+	CertificateArn string `json:"certificateArn" yaml:"certificateArn"`
+	Status         string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// replicateRollback is a structure that records resources created in replicating a certificate.
+type replicateRollback struct {
+	apis       RegionalACMAPI
+	rAPI       Route53API
+	results    []RegionalCertificateResult
+	recordSets []RecordSet
+
+	// existed are the ARNs of the certificates requested before with the same idempotency token,
+	// which are not deleted in rolling back
+	existed map[string]bool
+}
+
+// ReplicateCertificate issues an SSL certificate for the specified domain in each region.
+// The record that validates the domain is shared by the certificates, so it is created once in Route 53.
+// If it fails in any region, the certificates of all regions and the created records are deleted.
+func ReplicateCertificate(ctx context.Context, apis RegionalACMAPI, rAPI Route53API, regions []string, method, targetDomain, hostedDomain string, optFns ...func(*ReplicateCertificateOptions)) (ReplicateCertificateResult, error) {
+	o := ReplicateCertificateOptions{
+		Wait: true,
+	}
+	for _, fn := range optFns {
+		fn(&o)
+	}
+
+	if err := checkRegions(apis, regions); err != nil {
+		return ReplicateCertificateResult{}, err
+	}
+
+	result := ReplicateCertificateResult{
+		DomainName:       targetDomain,
+		HostedDomainName: hostedDomain,
+		ValidationMethod: method,
+	}
+	rb := replicateRollback{apis: apis, rAPI: rAPI, existed: map[string]bool{}}
+
+	io := IssueCertificateOptions{}
+	for _, fn := range o.IssueOptions {
+		fn(&io)
+	}
+	derived := io.IdempotencyToken == ""
+	if err := io.prepare(method, targetDomain, hostedDomain); err != nil {
+		return ReplicateCertificateResult{}, err
+	}
+
+	for _, r := range regions {
+		// the token can be changed in a region, so that each region starts with the same token
+		ro := io
+		arn, existed, err := requestIdempotentCertificate(ctx, apis[r], &ro, derived, func(o IssueCertificateOptions) (string, error) {
+			return requestCertificate(ctx, apis[r], method, targetDomain, hostedDomain, o)
+		})
+		if err != nil {
+			return ReplicateCertificateResult{}, rb.error(ctx, fmt.Sprintf("%s: %v", r, err))
+		}
+		rb.results = append(rb.results, RegionalCertificateResult{Region: r, CertificateArn: arn})
+		rb.existed[arn] = existed
+	}
+
+	if method != string(acmTypes.ValidationMethodEmail) {
+		hzID, err := getPublicHostedZoneIDByDomainName(ctx, rAPI, hostedDomain)
+		if err != nil {
+			return ReplicateCertificateResult{}, rb.error(ctx, err.Error())
+		}
+		if hzID == "" {
+			errMsg := fmt.Sprintf("Cannot get public hosted zone ID of %s in %s", hostedDomain, apiLabel(rAPI, ServiceRoute53))
+			return ReplicateCertificateResult{}, rb.error(ctx, errMsg)
+		}
+		result.HostedZoneID = hzID
+
+		checked := []RecordSet{}
+		for _, rr := range rb.results {
+			vRecords, err := describeValidationRecords(ctx, apis[rr.Region], rr.CertificateArn)
+			if err != nil {
+				return ReplicateCertificateResult{}, rb.error(ctx, fmt.Sprintf("%s: %v", rr.Region, err))
+			}
+
+			if result.ValidationRecordName == "" {
+				result.ValidationRecordName = vRecords[0].Name
+				result.ValidationRecordValue = vRecords[0].Value
+			}
+
+			for _, rs := range vRecords {
+				if containsRecordSet(checked, rs) {
+					continue
+				}
+				checked = append(checked, rs)
+
+				created, err := createValidationRecord(ctx, rAPI, hzID, rs.Name, rs.Value)
+				if err != nil {
+					return ReplicateCertificateResult{}, rb.error(ctx, err.Error())
+				}
+				// records that already existed are not deleted in rolling back
+				if created {
+					rb.recordSets = append(rb.recordSets, RecordSet{
+						HostedDomainName: hostedDomain,
+						Name:             rs.Name,
+						Value:            rs.Value,
+						Type:             string(route53Types.RRTypeCname),
+					})
+				}
+			}
+		}
+	}
+
+	if o.Wait {
+		if err := rb.waitIssued(ctx, o.WaitOptions); err != nil {
+			return ReplicateCertificateResult{}, rb.error(ctx, err.Error())
+		}
+	}
+
+	result.Regions = rb.results
+	return result, nil
+}
+
+// ReplicateImportedCertificate imports a certificate in each region.
+// If it fails in any region, the certificates imported in the other regions are deleted.
+func ReplicateImportedCertificate(ctx context.Context, apis RegionalACMAPI, regions []string, certificate, privateKey, certificateChain []byte) (ReplicateCertificateResult, error) {
+	if err := checkRegions(apis, regions); err != nil {
+		return ReplicateCertificateResult{}, err
+	}
+
+	result := ReplicateCertificateResult{}
+	rb := replicateRollback{apis: apis}
+
+	for _, r := range regions {
+		arn, err := ImportCertificate(ctx, apis[r], certificate, privateKey, certificateChain)
+		if err != nil {
+			return ReplicateCertificateResult{}, rb.error(ctx, fmt.Sprintf("%s: %v", r, err))
+		}
+		rb.results = append(rb.results, RegionalCertificateResult{Region: r, CertificateArn: arn})
+
+		c, err := GetCertificate(ctx, apis[r], arn)
+		if err != nil {
+			return ReplicateCertificateResult{}, rb.error(ctx, fmt.Sprintf("%s: %v", r, err))
+		}
+		rb.results[len(rb.results)-1].Status = c.Status
+		result.DomainName = c.DomainName
+	}
+
+	result.Regions = rb.results
+	return result, nil
+}
+
+// ReplicateCertificate issues an SSL certificate for the specified domain in all regions.
+func (m *MultiRegionGoACM) ReplicateCertificate(ctx context.Context, method, targetDomain, hostedDomain string, optFns ...func(*ReplicateCertificateOptions)) (ReplicateCertificateResult, error) {
+	return ReplicateCertificate(ctx, m.ACMAPIs(), m.Route53API(), m.Regions, method, targetDomain, hostedDomain, optFns...)
+}
+
+// ReplicateImportedCertificate imports a certificate in all regions.
+func (m *MultiRegionGoACM) ReplicateImportedCertificate(ctx context.Context, certificate, privateKey, certificateChain []byte) (ReplicateCertificateResult, error) {
+	return ReplicateImportedCertificate(ctx, m.ACMAPIs(), m.Regions, certificate, privateKey, certificateChain)
+}
+
+// Return an error if regions is empty or any region does not have a client.
+func checkRegions(apis RegionalACMAPI, regions []string) error {
+	if len(regions) == 0 {
+		return errors.New("regions must not be empty")
+	}
+	for _, r := range regions {
+		if _, ok := apis[r]; !ok {
+			return fmt.Errorf("ACM client of the region does not exists: %s", r)
+		}
+	}
+	return nil
+}
+
+// Wait until the certificates of all regions are issued concurrently.
+func (rb *replicateRollback) waitIssued(ctx context.Context, optFns []func(*WaitCertificateOptions)) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(rb.results))
+	)
+
+	for i := range rb.results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			rr := &rb.results[i]
+			c, err := WaitCertificateIssued(ctx, rb.apis[rr.Region], rr.CertificateArn, optFns...)
+			rr.Status = c.Status
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", rr.Region, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	msgs := []string{}
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+
+	return nil
+}
+
+// Delete the certificates and the records, and return an error with the result of the rollback.
+// The certificates that existed before the requests are kept, because they may be in use,
+// and so are the records they use, so that they can still be validated.
+func (rb *replicateRollback) error(ctx context.Context, errMsg string) error {
+	failed := []string{}
+	for _, rr := range rb.results {
+		if rb.existed[rr.CertificateArn] {
+			continue
+		}
+		in := acm.DeleteCertificateInput{
+			CertificateArn: aws.String(rr.CertificateArn),
+		}
+		if _, err := rb.apis[rr.Region].DeleteCertificate(ctx, &in); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", rr.Region, err))
+		}
+	}
+
+	kept, err := rb.keptRecordNames(ctx)
+	if err != nil {
+		failed = append(failed, fmt.Sprintf("kept the records, because the records of the kept certificates are unknown: %v", err))
+	} else {
+		for _, rs := range rb.recordSets {
+			if kept[rs.Name] {
+				continue
+			}
+			if err := DeleteRoute53RecordSet(ctx, nil, rb.rAPI, rs); err != nil {
+				failed = append(failed, err.Error())
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		errMsg += fmt.Sprintf("; Failed to rollback to replicate certificate: %s", strings.Join(failed, "; "))
+	} else {
+		errMsg += "; rollbacked to replicate certificate"
+	}
+	return errors.New(errMsg)
+}
+
+// Returns the names of the records that the certificates kept in rolling back use.
+func (rb *replicateRollback) keptRecordNames(ctx context.Context) (map[string]bool, error) {
+	names := map[string]bool{}
+	for _, rr := range rb.results {
+		if !rb.existed[rr.CertificateArn] {
+			continue
+		}
+		in := acm.DescribeCertificateInput{
+			CertificateArn: aws.String(rr.CertificateArn),
+		}
+		out, err := rb.apis[rr.Region].DescribeCertificate(ctx, &in)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rr.Region, err)
+		}
+		for _, rs := range validationRecordSets(out.Certificate) {
+			names[rs.Name] = true
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,293 @@
+package goacm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReplicateCertificate(t *testing.T) {
+	defer goacm.SetValidationRecordInterval(time.Millisecond)()
+
+	rs := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.test.example.com",
+		Value:            "_validation.value.test.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	regionalParams := func(region, status string) []goacm.MockACMParams {
+		return []goacm.MockACMParams{
+			{
+				Certificate: goacm.Certificate{
+					Arn:                 "arn:aws:acm:" + region + ":000000000000:certificate/this-is-a-sample-arn",
+					DomainName:          "test.example.com",
+					Status:              status,
+					Type:                string(types.CertificateTypeAmazonIssued),
+					ValidationMethod:    string(types.ValidationMethodDns),
+					ValidationRecordSet: rs,
+				},
+			},
+		}
+	}
+	rp := []goacm.MockRoute53Params{
+		{
+			RecordSet:    rs,
+			ChangeAction: route53Types.ChangeActionCreate,
+		},
+	}
+
+	cases := []struct {
+		name          string
+		apis          goacm.RegionalACMAPI
+		route53Params []goacm.MockRoute53Params
+		regions       []string
+		wantErr       bool
+		expect        goacm.ReplicateCertificateResult
+	}{
+		{
+			name: "normal",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1":      goacm.NewMockACMAPI(regionalParams("us-east-1", string(types.CertificateStatusIssued))),
+				"ap-northeast-1": goacm.NewMockACMAPI(regionalParams("ap-northeast-1", string(types.CertificateStatusIssued))),
+			},
+			route53Params: rp,
+			regions:       []string{"us-east-1", "ap-northeast-1"},
+			wantErr:       false,
+			expect: goacm.ReplicateCertificateResult{
+				DomainName:            "test.example.com",
+				HostedDomainName:      "example.com",
+				HostedZoneID:          "example-com",
+				ValidationMethod:      string(types.ValidationMethodDns),
+				ValidationRecordName:  rs.Name,
+				ValidationRecordValue: rs.Value,
+				Regions: []goacm.RegionalCertificateResult{
+					{
+						Region:         "us-east-1",
+						CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/this-is-a-sample-arn",
+						Status:         string(types.CertificateStatusIssued),
+					},
+					{
+						Region:         "ap-northeast-1",
+						CertificateArn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn",
+						Status:         string(types.CertificateStatusIssued),
+					},
+				},
+			},
+		},
+		{
+			name: "error: validation failed in a region",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1":      goacm.NewMockACMAPI(regionalParams("us-east-1", string(types.CertificateStatusIssued))),
+				"ap-northeast-1": goacm.NewMockACMAPI(regionalParams("ap-northeast-1", string(types.CertificateStatusFailed))),
+			},
+			route53Params: rp,
+			regions:       []string{"us-east-1", "ap-northeast-1"},
+			wantErr:       true,
+		},
+		{
+			name: "error: hosted zone not found",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": goacm.NewMockACMAPI(regionalParams("us-east-1", string(types.CertificateStatusIssued))),
+			},
+			route53Params: []goacm.MockRoute53Params{},
+			regions:       []string{"us-east-1"},
+			wantErr:       true,
+		},
+		{
+			name: "error: region without client",
+			apis: goacm.RegionalACMAPI{
+				"us-east-1": goacm.NewMockACMAPI(regionalParams("us-east-1", string(types.CertificateStatusIssued))),
+			},
+			route53Params: rp,
+			regions:       []string{"us-east-1", "eu-west-1"},
+			wantErr:       true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			res, err := goacm.ReplicateCertificate(context.TODO(), c.apis, goacm.NewMockRoute53API(c.route53Params), c.regions,
+				string(types.ValidationMethodDns), "test.example.com", "example.com",
+				func(o *goacm.ReplicateCertificateOptions) {
+					o.WaitOptions = []func(*goacm.WaitCertificateOptions){func(wo *goacm.WaitCertificateOptions) {
+						wo.Interval = time.Millisecond
+					}}
+				})
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, res)
+		})
+	}
+}
+
+func Test_ReplicateCertificate_Rollback(t *testing.T) {
+	defer goacm.SetValidationRecordInterval(time.Millisecond)()
+
+	rs := goacm.RecordSet{
+		HostedDomainName: "example.com",
+		Name:             "_validation.name.test.example.com",
+		Value:            "_validation.value.test.example.com",
+		Type:             string(route53Types.RRTypeCname),
+	}
+	regionalParams := func(region, status string, existed bool) goacm.MockACMParams {
+		mp := goacm.MockACMParams{
+			Certificate: goacm.Certificate{
+				Arn:                 "arn:aws:acm:" + region + ":000000000000:certificate/this-is-a-sample-arn",
+				DomainName:          "test.example.com",
+				Status:              status,
+				Type:                string(types.CertificateTypeAmazonIssued),
+				ValidationMethod:    string(types.ValidationMethodDns),
+				ValidationRecordSet: rs,
+			},
+		}
+		if existed {
+			mp.Certificate.CreatedAt = aws.Time(time.Now().Add(-time.Hour))
+		}
+		return mp
+	}
+
+	cases := []struct {
+		name          string
+		usEast1       goacm.MockACMParams
+		apNortheast1  goacm.MockACMParams
+		expectDeleted []string
+		expectActions []route53Types.ChangeAction
+	}{
+		{
+			name:          "error: the certificates and the record are deleted",
+			usEast1:       regionalParams("us-east-1", string(types.CertificateStatusIssued), false),
+			apNortheast1:  regionalParams("ap-northeast-1", string(types.CertificateStatusFailed), false),
+			expectDeleted: []string{"arn:aws:acm:us-east-1:000000000000:certificate/this-is-a-sample-arn", "arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn"},
+			expectActions: []route53Types.ChangeAction{route53Types.ChangeActionCreate, route53Types.ChangeActionDelete},
+		},
+		{
+			name:          "error: the certificate that existed and its record are kept",
+			usEast1:       regionalParams("us-east-1", string(types.CertificateStatusPendingValidation), true),
+			apNortheast1:  regionalParams("ap-northeast-1", string(types.CertificateStatusFailed), false),
+			expectDeleted: []string{"arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn"},
+			expectActions: []route53Types.ChangeAction{route53Types.ChangeActionCreate},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			deleted := []string{}
+			apis := goacm.RegionalACMAPI{}
+			for region, mp := range map[string]goacm.MockACMParams{"us-east-1": c.usEast1, "ap-northeast-1": c.apNortheast1} {
+				api := goacm.NewMockACMAPI([]goacm.MockACMParams{mp})
+				api.DeleteCertificateAPI = func(ctx context.Context, params *acm.DeleteCertificateInput, optFns ...func(*acm.Options)) (*acm.DeleteCertificateOutput, error) {
+					deleted = append(deleted, aws.ToString(params.CertificateArn))
+					return &acm.DeleteCertificateOutput{}, nil
+				}
+				apis[region] = api
+			}
+			rAPI := goacm.NewMockRoute53API([]goacm.MockRoute53Params{{RecordSet: rs, ChangeAction: route53Types.ChangeActionCreate}})
+			// the record exists after it is created
+			actions := []route53Types.ChangeAction{}
+			list := rAPI.ListResourceRecordSetsAPI
+			rAPI.ListResourceRecordSetsAPI = func(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+				if len(actions) > 0 {
+					return goacm.NewMockListResourceRecordSetsAPI([]goacm.MockRoute53Params{{RecordSet: rs}})(ctx, params, optFns...)
+				}
+				return list(ctx, params, optFns...)
+			}
+			rAPI.ChangeResourceRecordSetsAPI = func(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+				actions = append(actions, params.ChangeBatch.Changes[0].Action)
+				return &route53.ChangeResourceRecordSetsOutput{}, nil
+			}
+
+			_, err := goacm.ReplicateCertificate(context.TODO(), apis, rAPI, []string{"us-east-1", "ap-northeast-1"},
+				string(types.ValidationMethodDns), "test.example.com", "example.com",
+				func(o *goacm.ReplicateCertificateOptions) {
+					o.WaitOptions = []func(*goacm.WaitCertificateOptions){func(wo *goacm.WaitCertificateOptions) {
+						wo.Interval = time.Millisecond
+						wo.Timeout = 20 * time.Millisecond
+					}}
+				})
+			assert.Error(tt, err)
+			assert.Equal(tt, c.expectDeleted, deleted)
+			assert.Equal(tt, c.expectActions, actions)
+		})
+	}
+}
+
+func Test_ReplicateImportedCertificate(t *testing.T) {
+	regionalParams := func(region string) []goacm.MockACMParams {
+		return []goacm.MockACMParams{
+			{
+				Certificate: goacm.Certificate{
+					Arn:        "arn:aws:acm:" + region + ":000000000000:certificate/imported",
+					DomainName: "test.example.com",
+					Status:     string(types.CertificateStatusIssued),
+					Type:       string(types.CertificateTypeImported),
+				},
+			},
+		}
+	}
+	apis := goacm.RegionalACMAPI{
+		"us-east-1":      goacm.NewMockACMAPI(regionalParams("us-east-1")),
+		"ap-northeast-1": goacm.NewMockACMAPI(regionalParams("ap-northeast-1")),
+	}
+
+	cases := []struct {
+		name       string
+		regions    []string
+		privateKey []byte
+		wantErr    bool
+		expect     goacm.ReplicateCertificateResult
+	}{
+		{
+			name:       "normal",
+			regions:    []string{"us-east-1", "ap-northeast-1"},
+			privateKey: []byte("private-key"),
+			wantErr:    false,
+			expect: goacm.ReplicateCertificateResult{
+				DomainName: "test.example.com",
+				Regions: []goacm.RegionalCertificateResult{
+					{
+						Region:         "us-east-1",
+						CertificateArn: "arn:aws:acm:us-east-1:000000000000:certificate/imported",
+						Status:         string(types.CertificateStatusIssued),
+					},
+					{
+						Region:         "ap-northeast-1",
+						CertificateArn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/imported",
+						Status:         string(types.CertificateStatusIssued),
+					},
+				},
+			},
+		},
+		{
+			name:       "error: import failed",
+			regions:    []string{"us-east-1", "ap-northeast-1"},
+			privateKey: nil,
+			wantErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			res, err := goacm.ReplicateImportedCertificate(context.TODO(), apis, c.regions, []byte("certificate"), c.privateKey, nil)
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.Contains(tt, err.Error(), "rollbacked to replicate certificate")
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, res)
+		})
+	}
+}
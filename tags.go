@@ -0,0 +1,73 @@
+package goacm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmTypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// ListCertificateTags returns the tags of the certificate.
+func ListCertificateTags(ctx context.Context, api ACMListTagsForCertificateAPI, arn string) (map[string]string, error) {
+	in := acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+	out, err := api.ListTagsForCertificate(ctx, &in)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, t := range out.Tags {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+
+	return tags, nil
+}
+
+// AddCertificateTags adds the tags to the certificate. The values of existing keys are overwritten.
+func AddCertificateTags(ctx context.Context, api ACMAddTagsToCertificateAPI, arn string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	in := acm.AddTagsToCertificateInput{
+		CertificateArn: aws.String(arn),
+		Tags:           toACMTags(tags),
+	}
+	_, err := api.AddTagsToCertificate(ctx, &in)
+	return err
+}
+
+// RemoveCertificateTags removes the tags of the keys from the certificate.
+func RemoveCertificateTags(ctx context.Context, api ACMRemoveTagsFromCertificateAPI, arn string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	in := acm.RemoveTagsFromCertificateInput{
+		CertificateArn: aws.String(arn),
+	}
+	for _, k := range keys {
+		in.Tags = append(in.Tags, acmTypes.Tag{Key: aws.String(k)})
+	}
+	_, err := api.RemoveTagsFromCertificate(ctx, &in)
+	return err
+}
+
+// Returns ACM tags ordered by the key.
+func toACMTags(tags map[string]string) []acmTypes.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	t := make([]acmTypes.Tag, 0, len(keys))
+	for _, k := range keys {
+		t = append(t, acmTypes.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return t
+}
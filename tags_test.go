@@ -0,0 +1,106 @@
+package goacm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/michimani/goacm"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ListCertificateTags(t *testing.T) {
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn",
+			},
+			Tags: map[string]string{
+				"env":  "prod",
+				"team": "platform",
+			},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		arn     string
+		wantErr bool
+		expect  map[string]string
+	}{
+		{
+			name:   "normal",
+			arn:    ap[0].Certificate.Arn,
+			expect: map[string]string{"env": "prod", "team": "platform"},
+		},
+		{
+			name:    "error: not found",
+			arn:     "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found-arn",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tags, err := goacm.ListCertificateTags(context.TODO(), goacm.NewMockACMAPI(ap), c.arn)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, tags)
+		})
+	}
+}
+
+func Test_AddAndRemoveCertificateTags(t *testing.T) {
+	ap := []goacm.MockACMParams{
+		{
+			Certificate: goacm.Certificate{
+				Arn: "arn:aws:acm:ap-northeast-1:000000000000:certificate/this-is-a-sample-arn",
+			},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		arn     string
+		tags    map[string]string
+		keys    []string
+		wantErr bool
+	}{
+		{
+			name: "normal",
+			arn:  ap[0].Certificate.Arn,
+			tags: map[string]string{"env": "prod"},
+			keys: []string{"team"},
+		},
+		{
+			name: "normal: nothing to change",
+			arn:  "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found-arn",
+		},
+		{
+			name:    "error: not found",
+			arn:     "arn:aws:acm:ap-northeast-1:000000000000:certificate/not-found-arn",
+			tags:    map[string]string{"env": "prod"},
+			keys:    []string{"team"},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			api := goacm.NewMockACMAPI(ap)
+			addErr := goacm.AddCertificateTags(context.TODO(), api, c.arn, c.tags)
+			removeErr := goacm.RemoveCertificateTags(context.TODO(), api, c.arn, c.keys)
+			if c.wantErr {
+				assert.Error(tt, addErr)
+				assert.Error(tt, removeErr)
+				return
+			}
+
+			assert.NoError(tt, addErr)
+			assert.NoError(tt, removeErr)
+		})
+	}
+}
@@ -2,9 +2,12 @@ package goacm
 
 import (
 	"context"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/account"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 // ACMAPI is an interface that defines ACM API.
@@ -13,6 +16,14 @@ type ACMAPI interface {
 	ACMDescribeCertificateAPI
 	ACMDeleteCertificateAPI
 	ACMRequestCertificateAPI
+	ACMImportCertificateAPI
+	ACMExportCertificateAPI
+	ACMListTagsForCertificateAPI
+	ACMAddTagsToCertificateAPI
+	ACMRemoveTagsFromCertificateAPI
+	ACMUpdateCertificateOptionsAPI
+	ACMRenewCertificateAPI
+	ACMResendValidationEmailAPI
 }
 
 // Route53API is an interface that defines Route53 API.
@@ -42,6 +53,60 @@ type ACMRequestCertificateAPI interface {
 	RequestCertificate(ctx context.Context, params *acm.RequestCertificateInput, optFns ...func(*acm.Options)) (*acm.RequestCertificateOutput, error)
 }
 
+// ACMImportCertificateAPI is an interface that defines the set of ACM API operations required by the ImportCertificate function.
+type ACMImportCertificateAPI interface {
+	ImportCertificate(ctx context.Context, params *acm.ImportCertificateInput, optFns ...func(*acm.Options)) (*acm.ImportCertificateOutput, error)
+}
+
+// ACMExportCertificateAPI is an interface that defines the set of ACM API operations required by the ExportCertificate function.
+type ACMExportCertificateAPI interface {
+	ExportCertificate(ctx context.Context, params *acm.ExportCertificateInput, optFns ...func(*acm.Options)) (*acm.ExportCertificateOutput, error)
+}
+
+// ACMListTagsForCertificateAPI is an interface that defines the set of ACM API operations required by the ListCertificateTags function.
+type ACMListTagsForCertificateAPI interface {
+	ListTagsForCertificate(ctx context.Context, params *acm.ListTagsForCertificateInput, optFns ...func(*acm.Options)) (*acm.ListTagsForCertificateOutput, error)
+}
+
+// ACMAddTagsToCertificateAPI is an interface that defines the set of ACM API operations required by the AddCertificateTags function.
+type ACMAddTagsToCertificateAPI interface {
+	AddTagsToCertificate(ctx context.Context, params *acm.AddTagsToCertificateInput, optFns ...func(*acm.Options)) (*acm.AddTagsToCertificateOutput, error)
+}
+
+// ACMRemoveTagsFromCertificateAPI is an interface that defines the set of ACM API operations required by the RemoveCertificateTags function.
+type ACMRemoveTagsFromCertificateAPI interface {
+	RemoveTagsFromCertificate(ctx context.Context, params *acm.RemoveTagsFromCertificateInput, optFns ...func(*acm.Options)) (*acm.RemoveTagsFromCertificateOutput, error)
+}
+
+// ACMUpdateCertificateOptionsAPI is an interface that defines the set of ACM API operations required by the UpdateCertificateTransparencyLogging function.
+type ACMUpdateCertificateOptionsAPI interface {
+	UpdateCertificateOptions(ctx context.Context, params *acm.UpdateCertificateOptionsInput, optFns ...func(*acm.Options)) (*acm.UpdateCertificateOptionsOutput, error)
+}
+
+// ACMRenewCertificateAPI is an interface that defines the set of ACM API operations required by the RenewCertificate function.
+type ACMRenewCertificateAPI interface {
+	RenewCertificate(ctx context.Context, params *acm.RenewCertificateInput, optFns ...func(*acm.Options)) (*acm.RenewCertificateOutput, error)
+}
+
+// ACMResendValidationEmailAPI is an interface that defines the set of ACM API operations required by the ResendValidationEmail function.
+type ACMResendValidationEmailAPI interface {
+	ResendValidationEmail(ctx context.Context, params *acm.ResendValidationEmailInput, optFns ...func(*acm.Options)) (*acm.ResendValidationEmailOutput, error)
+}
+
+// ACMDescribeRenewCertificateAPI is an interface that defines the set of ACM API operations required by the RenewCertificate function,
+// that checks the certificate before renewing it.
+type ACMDescribeRenewCertificateAPI interface {
+	ACMDescribeCertificateAPI
+	ACMRenewCertificateAPI
+}
+
+// ACMDescribeResendValidationEmailAPI is an interface that defines the set of ACM API operations required by the ResendValidationEmail function,
+// that finds the domains pending validation before resending the emails.
+type ACMDescribeResendValidationEmailAPI interface {
+	ACMDescribeCertificateAPI
+	ACMResendValidationEmailAPI
+}
+
 // Route53ListHostedZonesAPI is an interface that defines the set of Route 53 API operations required by the ListHostedZone function.
 type Route53ListHostedZonesAPI interface {
 	ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error)
@@ -57,34 +122,103 @@ type Route53ChangeResourceRecordSetsAPI interface {
 	ChangeResourceRecordSets(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error)
 }
 
+// AccountListRegionsAPI is an interface that defines the set of Account API operations required by the ListEnabledRegions function.
+type AccountListRegionsAPI interface {
+	ListRegions(ctx context.Context, params *account.ListRegionsInput, optFns ...func(*account.Options)) (*account.ListRegionsOutput, error)
+}
+
+// STSGetCallerIdentityAPI is an interface that defines the set of STS API operations required to resolve the account of the clients.
+type STSGetCallerIdentityAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
 // RecordSet is a structure that reopresents a record set for Route 53.
 type RecordSet struct {
-	HostedDomainName string
-	Name             string
-	Value            string
-	Type             string
-	TTL              int64
+	HostedDomainName string `json:"hostedDomainName" yaml:"hostedDomainName"`
+	Name             string `json:"name" yaml:"name"`
+	Value            string `json:"value" yaml:"value"`
+	Type             string `json:"type" yaml:"type"`
+	TTL              int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
 }
 
 // Certificate is a structure that represents a Certificate.
 type Certificate struct {
-	Arn                 string
-	Region              string
-	DomainName          string
-	Type                string
-	Status              string
-	FailureReason       string
-	ValidationMethod    string
-	ValidationRecordSet RecordSet
+	Arn                     string     `json:"arn" yaml:"arn"`
+	Region                  string     `json:"region,omitempty" yaml:"region,omitempty"`
+	DomainName              string     `json:"domainName" yaml:"domainName"`
+	SubjectAlternativeNames []string   `json:"subjectAlternativeNames,omitempty" yaml:"subjectAlternativeNames,omitempty"`
+	Type                    string     `json:"type" yaml:"type"`
+	CertificateAuthorityArn string     `json:"certificateAuthorityArn,omitempty" yaml:"certificateAuthorityArn,omitempty"`
+	Status                  string     `json:"status" yaml:"status"`
+	KeyAlgorithm            string     `json:"keyAlgorithm,omitempty" yaml:"keyAlgorithm,omitempty"`
+	TransparencyLogging     string     `json:"transparencyLogging,omitempty" yaml:"transparencyLogging,omitempty"`
+	RenewalEligibility      string     `json:"renewalEligibility,omitempty" yaml:"renewalEligibility,omitempty"`
+	CreatedAt               *time.Time `json:"createdAt,omitempty" yaml:"createdAt,omitempty"`
+	NotAfter                *time.Time `json:"notAfter,omitempty" yaml:"notAfter,omitempty"`
+	InUseBy                 []string   `json:"inUseBy,omitempty" yaml:"inUseBy,omitempty"`
+	FailureReason           string     `json:"failureReason,omitempty" yaml:"failureReason,omitempty"`
+	ValidationMethod        string     `json:"validationMethod,omitempty" yaml:"validationMethod,omitempty"`
+	ValidationRecordSet     RecordSet  `json:"validationRecordSet" yaml:"validationRecordSet"`
+
+	// ValidationEmails are the approver addresses that receive validation emails of the domains.
+	ValidationEmails []string `json:"validationEmails,omitempty" yaml:"validationEmails,omitempty"`
 }
 
 // IssueCertificateResult is a structure that represents a reault of IssueCertificate.
 type IssueCertificateResult struct {
-	CertificateArn        string
-	DomainName            string
-	HostedDomainName      string
-	HosteZoneID           string
-	ValidationMethod      string
-	ValidationRecordName  string
-	ValidationRecordValue string
+	CertificateArn          string `json:"certificateArn" yaml:"certificateArn"`
+	DomainName              string `json:"domainName" yaml:"domainName"`
+	HostedDomainName        string `json:"hostedDomainName" yaml:"hostedDomainName"`
+	CertificateAuthorityArn string `json:"certificateAuthorityArn,omitempty" yaml:"certificateAuthorityArn,omitempty"`
+	HosteZoneID             string `json:"hostedZoneId,omitempty" yaml:"hostedZoneId,omitempty"`
+	ValidationMethod        string `json:"validationMethod" yaml:"validationMethod"`
+	ValidationRecordName    string `json:"validationRecordName,omitempty" yaml:"validationRecordName,omitempty"`
+	ValidationRecordValue   string `json:"validationRecordValue,omitempty" yaml:"validationRecordValue,omitempty"`
+	KeyAlgorithm            string `json:"keyAlgorithm,omitempty" yaml:"keyAlgorithm,omitempty"`
+	IdempotencyToken        string `json:"idempotencyToken,omitempty" yaml:"idempotencyToken,omitempty"`
+
+	// AlreadyExisted is true if ACM returned a certificate requested before with the same idempotency token,
+	// that is one created before the request. It is not deleted in rolling back the request.
+	AlreadyExisted bool `json:"alreadyExisted,omitempty" yaml:"alreadyExisted,omitempty"`
+}
+
+// IssueCertificateOptions is a structure that represents options for IssueCertificate and IssuePrivateCertificate.
+type IssueCertificateOptions struct {
+	// SubjectAlternativeNames are additional domain names of the certificate, such as "*.example.com".
+	// They must belong to the hosted domain to be validated by DNS.
+	SubjectAlternativeNames []string
+
+	// Tags are added to the certificate in requesting it.
+	Tags map[string]string
+
+	// KeyAlgorithm is an algorithm of the key pair, one of RequestableKeyAlgorithms.
+	// Default is RSA_2048.
+	KeyAlgorithm string
+
+	// TransparencyLogging is ENABLED or DISABLED. Default is ENABLED.
+	// Disable it for certificates of hostnames that must not appear in public certificate transparency logs.
+	TransparencyLogging string
+
+	// IdempotencyToken makes ACM return the certificate requested with the same token within one hour
+	// instead of requesting a new one. It has 1 to 32 letters, digits or underscores.
+	// If it is empty, a token derived from the request is used, so that retried requests do not create duplicates.
+	// The derived token is changed if ACM returns a certificate that has been deleted, and an explicit one is an error.
+	IdempotencyToken string
+}
+
+// ExportedCertificate is a structure that represents a certificate exported from ACM.
+// PrivateKey is encrypted with the passphrase specified in exporting.
+type ExportedCertificate struct {
+	Certificate      string
+	CertificateChain string
+	PrivateKey       string
+}
+
+// WaitCertificateOptions is a structure that represents options for WaitCertificateIssued.
+type WaitCertificateOptions struct {
+	// Interval is an interval of polling. Default is 15 seconds.
+	Interval time.Duration
+
+	// Timeout is a maximum time to wait. Default is 1 hour.
+	Timeout time.Duration
 }